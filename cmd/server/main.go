@@ -2,20 +2,31 @@ package main
 
 import (
 	"fmt"
+	"os"
 
+	"event_service/internal/cli"
 	"event_service/internal/initialize"
 )
 
 func main() {
+	// Administrative subcommands, e.g. `event_service topology verify`
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:]))
+	}
+
 	fmt.Println("Starting Event Service...")
 
 	// Initialize all components
-	initialize.Run()
+	if err := initialize.Run(); err != nil {
+		fmt.Printf("Event Service failed to start: %v\n", err)
+		initialize.Shutdown()
+		os.Exit(1)
+	}
 
 	fmt.Println("Event Service started successfully")
 
-	// Wait for consumers to finish (they handle graceful shutdown internally)
-	initialize.WaitForConsumers()
+	// Block until a shutdown signal, then drain consumers and close connections
+	initialize.WaitForShutdown()
 
 	fmt.Println("Event Service shutdown completed")
 }
@@ -1,6 +1,8 @@
 package global
 
 import (
+	"event_service/internal/breaker"
+	"event_service/internal/spool"
 	"event_service/pkg/setting"
 
 	"github.com/rabbitmq/amqp091-go"
@@ -8,9 +10,13 @@ import (
 )
 
 var (
-	Config *setting.Config
+	Config     *setting.Config
+	InstanceID string // Identifies this replica in consumer tags and leases
 
-	MongoDB             *mongo.Database     // MongoDB database connection
-	RabbitMQ            *amqp091.Connection // RabbitMQ connection
-	ActivityLogRabbitCh *amqp091.Channel    // RabbitMQ channel for activity logs
+	MongoClient    *mongo.Client               // MongoDB client, kept for disconnecting on shutdown
+	MongoDB        *mongo.Database             // MongoDB database connection
+	MongoBreaker   *breaker.Breaker            // Circuit breaker around MongoDB writes, nil when disabled
+	RabbitMQ       *amqp091.Connection         // RabbitMQ connection
+	RabbitChannels map[string]*amqp091.Channel // RabbitMQ channel per consumer
+	Spool          *spool.Spool                // Local disk spool, nil when disabled
 )
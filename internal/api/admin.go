@@ -0,0 +1,50 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"event_service/global"
+)
+
+// requireAdmin guards admin endpoints with the bearer token configured in
+// server.admin_token. Without a token the endpoints are disabled rather
+// than open.
+func requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := ""
+		if global.Config != nil {
+			token = global.Config.Server.AdminToken
+		}
+
+		if token == "" {
+			writeJSON(w, http.StatusForbidden, map[string]interface{}{
+				"error": "admin endpoints are disabled: server.admin_token is not set",
+			})
+			return
+		}
+
+		provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+				"error": "unauthorized",
+			})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// canDecrypt reports whether the request carries the X-Decrypt-Token
+// configured in encryption.decrypt_token. Without a configured token nobody
+// may read decrypted fields over HTTP.
+func canDecrypt(r *http.Request) bool {
+	if global.Config == nil || global.Config.Encryption.DecryptToken == "" {
+		return false
+	}
+
+	provided := r.Header.Get("X-Decrypt-Token")
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(global.Config.Encryption.DecryptToken)) == 1
+}
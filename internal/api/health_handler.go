@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"event_service/internal/health"
+	"event_service/internal/metrics"
+)
+
+type HealthHandler struct{}
+
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{}
+}
+
+// Liveness reports that the process is up, regardless of its dependencies
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+	})
+}
+
+// Readiness reports per-component startup progress and returns 503 until
+// every component is ready
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	state := "ready"
+	if !health.Ready() {
+		status = http.StatusServiceUnavailable
+		state = "not_ready"
+	}
+
+	writeJSON(w, status, map[string]interface{}{
+		"status":     state,
+		"components": health.Snapshot(),
+	})
+}
+
+// Metrics exposes the service metrics in the Prometheus text format
+func (h *HealthHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	if err := metrics.WriteText(w); err != nil {
+		fmt.Printf("Error writing metrics: %v\n", err)
+	}
+}
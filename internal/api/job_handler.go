@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"event_service/internal/common"
+	"event_service/internal/scheduler"
+)
+
+type JobHandler struct{}
+
+func NewJobHandler() *JobHandler {
+	return &JobHandler{}
+}
+
+// List returns every scheduled job with its next and last run
+func (h *JobHandler) List(w http.ResponseWriter, r *http.Request) {
+	s := scheduler.Current()
+	if s == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"error": "scheduler is not running",
+		})
+		return
+	}
+
+	jobs, err := s.Jobs(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"jobs": jobs,
+	})
+}
+
+// Run runs a job now and waits for it to finish
+func (h *JobHandler) Run(w http.ResponseWriter, r *http.Request) {
+	s := scheduler.Current()
+	if s == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"error": "scheduler is not running",
+		})
+		return
+	}
+
+	name := r.PathValue("name")
+	if err := s.Trigger(r.Context(), name); err != nil {
+		writeJSON(w, jobErrorStatus(err), map[string]interface{}{
+			"name":  name,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":   name,
+		"result": "success",
+	})
+}
+
+func jobErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, common.ErrUnknownJob):
+		return http.StatusNotFound
+	case errors.Is(err, common.ErrNotLeader), errors.Is(err, common.ErrJobRunning):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+	"event_service/internal/services"
+)
+
+type LegalHoldHandler struct{}
+
+func NewLegalHoldHandler() *LegalHoldHandler {
+	return &LegalHoldHandler{}
+}
+
+// List returns the active legal holds, or every hold with ?all=true
+func (h *LegalHoldHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !mongoConnected(w) {
+		return
+	}
+
+	holds, err := services.NewLegalHoldService().List(r.Context(), r.URL.Query().Get("all") == "true")
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"holds": holds,
+	})
+}
+
+// Create places a legal hold. The body is a hold with at least a reason,
+// createdBy and one scope field.
+func (h *LegalHoldHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !mongoConnected(w) {
+		return
+	}
+
+	var hold models.LegalHold
+	if err := json.NewDecoder(r.Body).Decode(&hold); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error": "invalid legal hold: " + err.Error(),
+		})
+		return
+	}
+
+	if err := services.NewLegalHoldService().Create(r.Context(), &hold); err != nil {
+		writeJSON(w, legalHoldErrorStatus(err), map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, hold)
+}
+
+// Release ends a legal hold. The body names who released it:
+// {"releasedBy": "..."}
+func (h *LegalHoldHandler) Release(w http.ResponseWriter, r *http.Request) {
+	if !mongoConnected(w) {
+		return
+	}
+
+	var body struct {
+		ReleasedBy string `json:"releasedBy"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error": "invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := services.NewLegalHoldService().Release(r.Context(), id, body.ReleasedBy); err != nil {
+		writeJSON(w, legalHoldErrorStatus(err), map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":     id,
+		"result": "released",
+	})
+}
+
+func legalHoldErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, common.ErrInvalidLegalHold):
+		return http.StatusBadRequest
+	case errors.Is(err, common.ErrLegalHoldNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// mongoConnected answers 503 until MongoDB is connected, since the router
+// is built before it
+func mongoConnected(w http.ResponseWriter) bool {
+	if global.MongoDB != nil {
+		return true
+	}
+
+	writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+		"error": "MongoDB is not connected",
+	})
+	return false
+}
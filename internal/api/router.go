@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// NewRouter builds the HTTP handler for the service's operational endpoints
+func NewRouter() http.Handler {
+	mux := http.NewServeMux()
+
+	healthHandler := NewHealthHandler()
+	mux.HandleFunc("GET /healthz", healthHandler.Liveness)
+	mux.HandleFunc("GET /readyz", healthHandler.Readiness)
+	mux.HandleFunc("GET /metrics", healthHandler.Metrics)
+
+	jobHandler := NewJobHandler()
+	mux.HandleFunc("GET /admin/jobs", requireAdmin(jobHandler.List))
+	mux.HandleFunc("POST /admin/jobs/{name}/run", requireAdmin(jobHandler.Run))
+
+	timelineHandler := NewTimelineHandler()
+	mux.HandleFunc("GET /admin/users/{userId}/timeline", requireAdmin(timelineHandler.UserTimeline))
+
+	legalHoldHandler := NewLegalHoldHandler()
+	mux.HandleFunc("GET /admin/legal-holds", requireAdmin(legalHoldHandler.List))
+	mux.HandleFunc("POST /admin/legal-holds", requireAdmin(legalHoldHandler.Create))
+	mux.HandleFunc("POST /admin/legal-holds/{id}/release", requireAdmin(legalHoldHandler.Release))
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Printf("Error encoding HTTP response: %v\n", err)
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"event_service/internal/common"
+	"event_service/internal/services"
+)
+
+type TimelineHandler struct{}
+
+func NewTimelineHandler() *TimelineHandler {
+	return &TimelineHandler{}
+}
+
+// UserTimeline returns the activities a user performed or was the subject
+// of, newest first. Optional query parameters: topic (repeatable), limit,
+// cursor (nextCursor of the previous page) and decrypt=true, which also
+// requires the X-Decrypt-Token header.
+func (h *TimelineHandler) UserTimeline(w http.ResponseWriter, r *http.Request) {
+	if !mongoConnected(w) {
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error": "limit must be a positive integer",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	decrypt := query.Get("decrypt") == "true"
+	if decrypt && !canDecrypt(r) {
+		writeJSON(w, http.StatusForbidden, map[string]interface{}{
+			"error": "not authorized to decrypt",
+		})
+		return
+	}
+
+	userID := r.PathValue("userId")
+	timeline, err := services.NewTimelineService().GetUserTimeline(r.Context(), userID, query["topic"], query.Get("cursor"), limit, decrypt)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, common.ErrInvalidCursor) {
+			status = http.StatusBadRequest
+		}
+		writeJSON(w, status, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, timeline)
+}
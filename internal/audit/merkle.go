@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// Merkle trees as in RFC 6962: leaves and inner nodes are hashed with
+// different prefixes so a leaf can't pass for a node.
+
+// LeafHash hashes one leaf of the tree
+func LeafHash(data []byte) []byte {
+	sum := sha256.Sum256(append([]byte{0x00}, data...))
+	return sum[:]
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// RootHash computes the root over leaf hashes, in order
+func RootHash(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	case 1:
+		return leaves[0]
+	}
+
+	k := splitPoint(len(leaves))
+	return nodeHash(RootHash(leaves[:k]), RootHash(leaves[k:]))
+}
+
+// InclusionPath returns the sibling hashes proving that the leaf at index
+// is part of the tree, from the leaf up
+func InclusionPath(leaves [][]byte, index int) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+
+	k := splitPoint(len(leaves))
+	if index < k {
+		return append(InclusionPath(leaves[:k], index), RootHash(leaves[k:]))
+	}
+	return append(InclusionPath(leaves[k:], index-k), RootHash(leaves[:k]))
+}
+
+// VerifyInclusion checks an inclusion path against a root (RFC 9162,
+// section 2.1.3.2)
+func VerifyInclusion(leafHash []byte, index, size int, path [][]byte, root []byte) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+
+	fn, sn := index, size-1
+	r := leafHash
+	for _, p := range path {
+		if sn == 0 {
+			return false
+		}
+
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+
+	return sn == 0 && bytes.Equal(r, root)
+}
+
+// splitPoint is the largest power of two smaller than n
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
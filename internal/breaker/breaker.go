@@ -0,0 +1,244 @@
+package breaker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"event_service/internal/common"
+	"event_service/internal/metrics"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	breakerState = metrics.NewGauge("event_service_circuit_breaker_state",
+		"Circuit breaker state (0=closed, 1=open, 2=half-open)", "name")
+	breakerTransitionsTotal = metrics.NewCounter("event_service_circuit_breaker_transitions_total",
+		"Circuit breaker state transitions", "name", "to")
+)
+
+type Options struct {
+	WindowSize           int           // number of recent calls the failure rate is computed over
+	MinRequests          int           // calls needed in the window before the breaker may open
+	FailureRateThreshold float64       // 0..1
+	OpenDuration         time.Duration // how long to stay open before allowing probes
+	HalfOpenMaxProbes    int           // concurrent calls allowed while half-open
+}
+
+// Breaker is a failure-rate circuit breaker. While closed every call is
+// allowed and its outcome recorded in a sliding window; once the failure
+// rate crosses the threshold it opens and rejects calls with
+// common.ErrCircuitOpen. After OpenDuration it lets a limited number of probe
+// calls through: a successful probe closes it, a failed one reopens it.
+type Breaker struct {
+	name string
+	opts Options
+
+	mu        sync.Mutex
+	state     State
+	outcomes  []bool // ring buffer, true = failure
+	next      int
+	count     int
+	failures  int
+	probes    int
+	listeners []func(State)
+
+	changed chan struct{}
+}
+
+func New(name string, opts Options) *Breaker {
+	if opts.WindowSize <= 0 {
+		opts.WindowSize = 20
+	}
+	if opts.MinRequests <= 0 || opts.MinRequests > opts.WindowSize {
+		opts.MinRequests = opts.WindowSize
+	}
+	if opts.FailureRateThreshold <= 0 || opts.FailureRateThreshold > 1 {
+		opts.FailureRateThreshold = 0.5
+	}
+	if opts.OpenDuration <= 0 {
+		opts.OpenDuration = 30 * time.Second
+	}
+	if opts.HalfOpenMaxProbes <= 0 {
+		opts.HalfOpenMaxProbes = 1
+	}
+
+	b := &Breaker{
+		name:     name,
+		opts:     opts,
+		outcomes: make([]bool, opts.WindowSize),
+		changed:  make(chan struct{}, 1),
+	}
+	breakerState.Set(float64(StateClosed), name)
+
+	go b.notifyListeners()
+	return b
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// OnStateChange registers fn to be called with the new state after
+// transitions. Rapid transitions are coalesced so listeners see the latest
+// state. Listeners run on a single goroutine and must not block.
+func (b *Breaker) OnStateChange(fn func(State)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listeners = append(b.listeners, fn)
+}
+
+// Allow reports whether a call may proceed. Every allowed call must be
+// followed by exactly one Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		return fmt.Errorf("%w: %s", common.ErrCircuitOpen, b.name)
+	case StateHalfOpen:
+		if b.probes >= b.opts.HalfOpenMaxProbes {
+			return fmt.Errorf("%w: %s (probe in progress)", common.ErrCircuitOpen, b.name)
+		}
+		b.probes++
+	}
+
+	return nil
+}
+
+// Record reports the outcome of an allowed call
+func (b *Breaker) Record(failure bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateClosed:
+		b.recordOutcome(failure)
+		if b.count >= b.opts.MinRequests &&
+			float64(b.failures)/float64(b.count) >= b.opts.FailureRateThreshold {
+			b.open()
+		}
+
+	case StateHalfOpen:
+		if b.probes > 0 {
+			b.probes--
+		}
+		if failure {
+			b.open()
+		} else {
+			b.resetWindow()
+			b.setState(StateClosed)
+		}
+
+	case StateOpen:
+		// Late result of a call allowed before the breaker opened
+	}
+}
+
+func (b *Breaker) recordOutcome(failure bool) {
+	if b.count == len(b.outcomes) {
+		if b.outcomes[b.next] {
+			b.failures--
+		}
+	} else {
+		b.count++
+	}
+
+	b.outcomes[b.next] = failure
+	if failure {
+		b.failures++
+	}
+	b.next = (b.next + 1) % len(b.outcomes)
+}
+
+func (b *Breaker) resetWindow() {
+	for i := range b.outcomes {
+		b.outcomes[i] = false
+	}
+	b.next = 0
+	b.count = 0
+	b.failures = 0
+}
+
+// open must be called with b.mu held
+func (b *Breaker) open() {
+	b.probes = 0
+	b.setState(StateOpen)
+
+	time.AfterFunc(b.opts.OpenDuration, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if b.state == StateOpen {
+			b.setState(StateHalfOpen)
+		}
+	})
+}
+
+// setState must be called with b.mu held
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+
+	fmt.Printf("Circuit breaker %s: %s -> %s\n", b.name, b.state, state)
+	b.state = state
+	breakerState.Set(float64(state), b.name)
+	breakerTransitionsTotal.Inc(b.name, state.String())
+
+	// Coalesce notifications: listeners always see the latest state even
+	// if several transitions happen while they are running
+	select {
+	case b.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (b *Breaker) notifyListeners() {
+	notified := StateClosed
+	for range b.changed {
+		b.mu.Lock()
+		state := b.state
+		listeners := make([]func(State), len(b.listeners))
+		copy(listeners, b.listeners)
+		b.mu.Unlock()
+
+		if state == notified {
+			continue
+		}
+		notified = state
+
+		for _, fn := range listeners {
+			fn(state)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"event_service/internal/initialize"
+	"event_service/internal/services"
+)
+
+const auditUsage = "Usage: event_service audit <verify [-partition NAME]|prove -event ID [-checkpoint ID]>"
+
+func runAudit(args []string) int {
+	if len(args) == 0 {
+		fmt.Println(auditUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "verify":
+		return runAuditVerify(args[1:])
+	case "prove":
+		return runAuditProve(args[1:])
+	default:
+		fmt.Println(auditUsage)
+		return 2
+	}
+}
+
+// connectAudit loads the config and connects to MongoDB; the returned
+// function disconnects
+func connectAudit() (func(), bool) {
+	initialize.LoadConfig()
+
+	connectCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := initialize.InitMongoDB(connectCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "MongoDB connection failed: %v\n", err)
+		return nil, false
+	}
+	return initialize.DisconnectMongoDB, true
+}
+
+func runAuditVerify(args []string) int {
+	flags := flag.NewFlagSet("audit verify", flag.ContinueOnError)
+	partition := flags.String("partition", "", "verify one chain partition instead of all")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	disconnect, ok := connectAudit()
+	if !ok {
+		return 1
+	}
+	defer disconnect()
+
+	reports, err := services.NewAuditService().VerifyChain(context.Background(), *partition)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Verification failed: %v\n", err)
+		return 1
+	}
+
+	broken := 0
+	for _, report := range reports {
+		state := "OK"
+		if !report.Intact() {
+			state = "BROKEN"
+			broken++
+		}
+		fmt.Printf("%s: %s, %d logs, seq %d-%d, head %d\n",
+			report.Partition, state, report.Records, report.FirstSeq, report.LastSeq, report.HeadSeq)
+
+		for _, issue := range report.Issues {
+			fmt.Printf("  %-12s seq %-8d %-36s %s\n", issue.Kind, issue.Seq, issue.EventID, issue.Detail)
+		}
+	}
+
+	fmt.Printf("%d partitions verified, %d broken\n", len(reports), broken)
+	if broken > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runAuditProve(args []string) int {
+	flags := flag.NewFlagSet("audit prove", flag.ContinueOnError)
+	eventID := flags.String("event", "", "event id of the log to prove")
+	checkpointID := flags.String("checkpoint", "", "checkpoint to prove against (default: the one covering the log)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *eventID == "" {
+		fmt.Println(auditUsage)
+		return 2
+	}
+
+	disconnect, ok := connectAudit()
+	if !ok {
+		return 1
+	}
+	defer disconnect()
+
+	proof, err := services.NewCheckpointService().Prove(context.Background(), *eventID, *checkpointID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Proof failed: %v\n", err)
+		return 1
+	}
+
+	output, err := json.MarshalIndent(proof, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Proof failed: %v\n", err)
+		return 1
+	}
+
+	fmt.Println(string(output))
+	return 0
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []command{
+	{"topology", "Declare or verify the RabbitMQ topology", runTopology},
+	{"jobs", "List scheduled jobs or run one now", runJobs},
+	{"rollups", "Rebuild daily activity rollups for a date range", runRollups},
+	{"erase", "Anonymize or delete a user's activity logs", runErase},
+	{"holds", "List, create or release legal holds", runHolds},
+	{"keys", "List, rotate or rewrap field encryption data keys", runKeys},
+	{"audit", "Verify the hash chain and prove logs against signed checkpoints", runAudit},
+}
+
+// Run executes the subcommand named by args[0] and returns the process exit
+// code
+func Run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage()
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	usage()
+	return 2
+}
+
+func usage() {
+	fmt.Println("Usage: event_service [command] [arguments]")
+	fmt.Println()
+	fmt.Println("Without a command the service starts consuming events.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	for _, cmd := range commands {
+		fmt.Printf("  %-12s %s\n", cmd.name, cmd.summary)
+	}
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/initialize"
+	"event_service/internal/services"
+)
+
+func runErase(args []string) int {
+	flags := flag.NewFlagSet("erase", flag.ContinueOnError)
+	mode := flags.String("mode", "", "anonymize or delete, defaults to erasure.mode")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: event_service erase [-mode anonymize|delete] USER_ID")
+		return 2
+	}
+
+	initialize.LoadConfig()
+
+	if err := services.ValidateErasureConfig(global.Config.Erasure); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid erasure configuration: %v\n", err)
+		return 1
+	}
+
+	connectCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := initialize.InitMongoDB(connectCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "MongoDB connection failed: %v\n", err)
+		return 1
+	}
+	defer initialize.DisconnectMongoDB()
+
+	// Progress is checkpointed per batch, so an interrupted erasure resumes
+	// when the command is run again
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	receipt, err := services.NewErasureService().EraseUser(ctx, flags.Arg(0), *mode, "cli")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erasure failed: %v\n", err)
+		if receipt != nil {
+			fmt.Fprintf(os.Stderr, "Progress saved under %s; run the command again to resume\n", receipt.Subject)
+		}
+		return 1
+	}
+
+	fmt.Printf("Receipt %s: mode %s, %d matched, %d anonymized, %d deleted over %d run(s); %d kept for legal hold\n",
+		receipt.Subject, receipt.Mode, receipt.Matched, receipt.Anonymized, receipt.Deleted, receipt.Runs, receipt.Held)
+	return 0
+}
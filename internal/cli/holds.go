@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"event_service/internal/initialize"
+	"event_service/internal/models"
+	"event_service/internal/services"
+)
+
+const holdsUsage = `Usage:
+  event_service holds list [-all]
+  event_service holds create -reason TEXT -by NAME [-workspace ID] [-user ID] [-topic TOPIC] [-from TIME] [-to TIME]
+  event_service holds release -by NAME HOLD_ID
+
+TIME is RFC 3339 or YYYY-MM-DD (UTC).`
+
+func runHolds(args []string) int {
+	if len(args) == 0 {
+		fmt.Println(holdsUsage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("holds "+args[0], flag.ContinueOnError)
+	all := flags.Bool("all", false, "include released holds")
+	reason := flags.String("reason", "", "why the records are held")
+	by := flags.String("by", "", "who creates or releases the hold")
+	workspace := flags.String("workspace", "", "hold the logs of this workspace")
+	user := flags.String("user", "", "hold the logs referencing this user")
+	topic := flags.String("topic", "", "hold the logs of this topic")
+	fromFlag := flags.String("from", "", "hold logs from this time")
+	toFlag := flags.String("to", "", "hold logs up to this time")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	var hold models.LegalHold
+	switch args[0] {
+	case "list":
+	case "create":
+		from, err := parseHoldTime(*fromFlag, false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -from: %v\n", err)
+			return 2
+		}
+		to, err := parseHoldTime(*toFlag, true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -to: %v\n", err)
+			return 2
+		}
+		hold = models.LegalHold{
+			WorkspaceID: *workspace,
+			UserID:      *user,
+			Topic:       *topic,
+			From:        from,
+			To:          to,
+			Reason:      *reason,
+			CreatedBy:   *by,
+		}
+	case "release":
+		if flags.NArg() != 1 {
+			fmt.Println(holdsUsage)
+			return 2
+		}
+	default:
+		fmt.Println(holdsUsage)
+		return 2
+	}
+
+	initialize.LoadConfig()
+
+	connectCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := initialize.InitMongoDB(connectCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "MongoDB connection failed: %v\n", err)
+		return 1
+	}
+	defer initialize.DisconnectMongoDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	holdService := services.NewLegalHoldService()
+
+	switch args[0] {
+	case "create":
+		if err := holdService.Create(ctx, &hold); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create legal hold: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Legal hold %s created\n", hold.ID.Hex())
+		return 0
+
+	case "release":
+		if err := holdService.Release(ctx, flags.Arg(0), *by); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to release legal hold: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Legal hold %s released\n", flags.Arg(0))
+		return 0
+	}
+
+	holds, err := holdService.List(ctx, *all)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list legal holds: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("%-24s %-9s %-40s %-16s %s\n", "ID", "STATUS", "SCOPE", "CREATED BY", "REASON")
+	for _, hold := range holds {
+		status := "active"
+		if !hold.Active() {
+			status = "released"
+		}
+		fmt.Printf("%-24s %-9s %-40s %-16s %s\n", hold.ID.Hex(), status, holdScope(hold), hold.CreatedBy, hold.Reason)
+	}
+	return 0
+}
+
+// parseHoldTime parses an RFC 3339 time or a date. A date used as the end
+// of the range covers the whole day.
+func parseHoldTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+func holdScope(hold models.LegalHold) string {
+	scope := ""
+	add := func(key, value string) {
+		if value == "" {
+			return
+		}
+		if scope != "" {
+			scope += " "
+		}
+		scope += key + "=" + value
+	}
+
+	add("workspace", hold.WorkspaceID)
+	add("user", hold.UserID)
+	add("topic", hold.Topic)
+	if !hold.From.IsZero() {
+		add("from", hold.From.Format(time.RFC3339))
+	}
+	if !hold.To.IsZero() {
+		add("to", hold.To.Format(time.RFC3339))
+	}
+	return scope
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"event_service/internal/initialize"
+)
+
+func runJobs(args []string) int {
+	if len(args) == 0 || (args[0] == "run" && len(args) != 2) {
+		fmt.Println("Usage: event_service jobs <list|run NAME>")
+		return 2
+	}
+
+	initialize.LoadConfig()
+	initialize.InitInstanceID()
+
+	connectCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := initialize.InitMongoDB(connectCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "MongoDB connection failed: %v\n", err)
+		return 1
+	}
+	defer initialize.DisconnectMongoDB()
+
+	s, err := initialize.NewScheduler()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid scheduler configuration: %v\n", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "list":
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		jobs, err := s.Jobs(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list jobs: %v\n", err)
+			return 1
+		}
+
+		fmt.Printf("%-24s %-16s %-8s %-25s %s\n", "JOB", "SCHEDULE", "RESULT", "LAST RUN", "ERROR")
+		for _, job := range jobs {
+			result, lastRun, lastError := "-", "-", ""
+			if job.LastRun != nil {
+				result = job.LastRun.LastResult
+				lastRun = job.LastRun.LastStartedAt.Format(time.RFC3339)
+				lastError = job.LastRun.LastError
+			}
+			fmt.Printf("%-24s %-16s %-8s %-25s %s\n", job.Name, job.Schedule, result, lastRun, lastError)
+		}
+		return 0
+
+	case "run":
+		// Runs here and now, holding the job lease for the run; fails while a
+		// replica holds it
+		if err := s.RunLocally(context.Background(), args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Job %s failed: %v\n", args[1], err)
+			return 1
+		}
+		fmt.Printf("Job %s succeeded\n", args[1])
+		return 0
+
+	default:
+		fmt.Printf("Unknown jobs command %q\n", args[0])
+		return 2
+	}
+}
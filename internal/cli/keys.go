@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"event_service/internal/initialize"
+)
+
+func runKeys(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("Usage: event_service keys <list|rotate|rewrap>")
+		return 2
+	}
+
+	initialize.LoadConfig()
+
+	connectCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := initialize.InitMongoDB(connectCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "MongoDB connection failed: %v\n", err)
+		return 1
+	}
+	defer initialize.DisconnectMongoDB()
+
+	encryptor, err := initialize.NewEncryptor()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid encryption configuration: %v\n", err)
+		return 1
+	}
+	keyring := encryptor.Keyring()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	switch args[0] {
+	case "list":
+		keys, err := keyring.Keys(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list data keys: %v\n", err)
+			return 1
+		}
+
+		fmt.Printf("%-36s %-20s %-25s %s\n", "DATA KEY", "MASTER KEY", "CREATED", "ACTIVE")
+		for i, key := range keys {
+			active := ""
+			if i == len(keys)-1 {
+				active = "yes"
+			}
+			fmt.Printf("%-36s %-20s %-25s %s\n", key.ID, key.MasterKeyID, key.CreatedAt.Format(time.RFC3339), active)
+		}
+		return 0
+
+	case "rotate":
+		key, err := keyring.Rotate(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to rotate data key: %v\n", err)
+			return 1
+		}
+		fmt.Printf("New documents will be encrypted with %s once running instances refresh their key (within 5 minutes)\n", key.ID)
+		return 0
+
+	case "rewrap":
+		rewrapped, err := keyring.Rewrap(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Rewrap failed after %d data keys: %v\n", rewrapped, err)
+			return 1
+		}
+		fmt.Printf("Rewrapped %d data keys with master key %s\n", rewrapped, keyring.MasterKeyID())
+		return 0
+
+	default:
+		fmt.Printf("Unknown keys command %q\n", args[0])
+		return 2
+	}
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/initialize"
+	"event_service/internal/topology"
+)
+
+func runTopology(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("Usage: event_service topology <declare|verify>")
+		return 2
+	}
+
+	initialize.LoadConfig()
+	t := topology.Resolve(global.Config.RabbitMQ, global.Config.Topology)
+
+	switch args[0] {
+	case "declare":
+		if err := initialize.DeclareTopology(); err != nil {
+			fmt.Fprintf(os.Stderr, "Topology declaration failed: %v\n", err)
+			return 1
+		}
+		return 0
+
+	case "verify":
+		cfg := global.Config.RabbitMQ
+		if cfg.ManagementURL == "" {
+			fmt.Fprintln(os.Stderr, "rabbitmq.management_url is required to verify the topology")
+			return 2
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		client := topology.NewManagementClient(cfg.ManagementURL, "/", cfg.User, cfg.Password)
+		drifts, err := topology.Verify(ctx, client, t)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Topology verification failed: %v\n", err)
+			return 1
+		}
+
+		if len(drifts) == 0 {
+			fmt.Printf("Topology matches: %d exchanges, %d queues, %d bindings\n",
+				len(t.Exchanges), len(t.Queues), len(t.Bindings))
+			return 0
+		}
+
+		fmt.Printf("Topology drift detected (%d):\n", len(drifts))
+		for _, drift := range drifts {
+			fmt.Printf("  - %s\n", drift)
+		}
+		return 1
+
+	default:
+		fmt.Printf("Unknown topology command %q\n", args[0])
+		return 2
+	}
+}
@@ -1,11 +1,27 @@
 package common
 
 const (
+	// Event Sources
+	SourceIAM      = "iam"
+	SourceAppStore = "app_store"
+
 	// Queue Names
-	ActivityLogQueue = "iam_activity_log_queue"
+	ActivityLogQueue         = "iam_activity_log_queue"
+	AppStoreActivityLogQueue = "app_store_activity_log_queue"
 
 	// Exchange Names
-	IAMEventsExchange = "iam_events_topic"
+	IAMEventsExchange      = "iam_events_topic"
+	AppStoreEventsExchange = "app_store_events_topic"
+
+	// Message Headers
+	HeaderRetryCount         = "x-retry-count"
+	HeaderOriginalRoutingKey = "x-original-routing-key"
+	HeaderOriginExchange     = "x-origin-exchange"
+	HeaderRetryReason        = "x-retry-reason"
+	HeaderDeadLetterReason   = "x-dead-letter-reason"
+	HeaderOriginalUserID     = "x-original-user-id"
+	HeaderDeliveryCount      = "x-delivery-count" // set by quorum queues
+	HeaderStreamOffset       = "x-stream-offset"  // set by streams
 
 	// Binding Keys
 	ActivityLogBindingKey = "#.log"
@@ -22,5 +38,17 @@ const (
 	MemberRoleChangedLog = "member.role_changed.log"
 
 	// Collection Names
-	ActivityLogCollection = "activity_logs"
+	ActivityLogCollection    = "activity_logs"
+	StreamOffsetCollection   = "stream_offsets"
+	LeaseCollection          = "leases"
+	JobStatusCollection      = "scheduled_jobs"
+	RollupCollection         = "activity_rollups"
+	ErasureCollection        = "erasure_receipts"
+	LegalHoldCollection      = "legal_holds"
+	DataKeyCollection        = "data_keys"
+	ChainHeadCollection      = "audit_chain_heads"
+	CheckpointCollection     = "audit_checkpoints"
+	CheckpointLeafCollection = "audit_checkpoint_leaves"
+	ChainTombstoneCollection = "audit_chain_tombstones"
+	QuarantineCollection     = "quarantined_events"
 )
@@ -7,6 +7,7 @@ var (
 	ErrMongoConnection = errors.New("failed to connect to MongoDB")
 	ErrMongoInsert     = errors.New("failed to insert document to MongoDB")
 	ErrMongoQuery      = errors.New("failed to query MongoDB")
+	ErrCircuitOpen     = errors.New("circuit breaker is open")
 
 	// RabbitMQ errors
 	ErrRabbitConnection = errors.New("failed to connect to RabbitMQ")
@@ -16,11 +17,44 @@ var (
 	ErrRabbitAck        = errors.New("failed to acknowledge message")
 	ErrRabbitNack       = errors.New("failed to negative acknowledge message")
 
+	// Consumer errors
+	ErrUnknownHandler = errors.New("unknown message handler")
+
 	// Event processing errors
 	ErrEventDeserialization = errors.New("failed to deserialize event")
 	ErrEventProcessing      = errors.New("failed to process event")
 	ErrEventValidation      = errors.New("event validation failed")
 
+	// Spool errors
+	ErrSpoolFull    = errors.New("spool size limit reached")
+	ErrSpoolWrite   = errors.New("failed to write to spool")
+	ErrSpoolCorrupt = errors.New("spool segment is corrupt")
+
+	// Query errors
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+	// Legal hold errors
+	ErrInvalidLegalHold  = errors.New("invalid legal hold")
+	ErrLegalHoldNotFound = errors.New("legal hold not found or already released")
+
+	// Encryption errors
+	ErrEncryption = errors.New("field encryption failed")
+	ErrDecryption = errors.New("field decryption failed")
+
+	// Schema errors
+	ErrUpcast = errors.New("payload upcast failed")
+
+	// Audit errors
+	ErrCheckpointNotFound = errors.New("audit checkpoint not found")
+	ErrCheckpointMismatch = errors.New("stored leaves no longer match the audit checkpoint")
+	ErrCheckpointFork     = errors.New("another checkpoint already follows the previous one")
+
+	// Scheduler errors
+	ErrUnknownJob = errors.New("unknown job")
+	ErrJobRunning = errors.New("job is already running")
+	ErrNotLeader  = errors.New("lease is held by another instance")
+	ErrStaleLease = errors.New("lease token is no longer current")
+
 	// Configuration errors
 	ErrConfigLoad       = errors.New("failed to load configuration")
 	ErrConfigValidation = errors.New("configuration validation failed")
@@ -0,0 +1,43 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+
+	"event_service/internal/common"
+	"event_service/internal/envelope"
+	"event_service/internal/services"
+)
+
+// activityLogHandler decodes generic events and stores them as activity logs
+type activityLogHandler struct {
+	logService services.LogService
+	guard      *sourceGuard
+}
+
+func NewActivityLogHandler() MessageHandler {
+	return &activityLogHandler{
+		logService: services.NewLogService(),
+		guard:      newSourceGuard(HandlerActivityLog),
+	}
+}
+
+func (h *activityLogHandler) Handle(ctx context.Context, message *envelope.Message) error {
+	event, err := decodeEvent(message)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Processing event: %s with topic: %s\n", event.EventID, event.Topic)
+
+	if admitted, err := h.guard.admit(ctx, message, event); !admitted {
+		return err
+	}
+
+	err = h.logService.ProcessEvent(ctx, event)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrEventProcessing, err)
+	}
+
+	return nil
+}
@@ -2,8 +2,12 @@ package consumers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
+
+	"event_service/internal/health"
 )
 
 type ConsumerManager struct {
@@ -11,12 +15,16 @@ type ConsumerManager struct {
 	wg        sync.WaitGroup
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	mu     sync.RWMutex
+	failed map[string]error // consumers whose Start failed, by name
 }
 
 func NewConsumerManager() *ConsumerManager {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &ConsumerManager{
 		consumers: make([]Consumer, 0),
+		failed:    make(map[string]error),
 		ctx:       ctx,
 		cancel:    cancel,
 	}
@@ -27,57 +35,118 @@ func (cm *ConsumerManager) RegisterConsumer(consumer Consumer) {
 	fmt.Printf("Registered consumer: %s\n", consumer.GetName())
 }
 
+// StartAll starts every consumer and waits until each has subscribed or
+// failed to. A consumer that fails to start doesn't stop the others; the
+// "consumers" readiness component is marked failed with its error, and the
+// joined errors are returned.
 func (cm *ConsumerManager) StartAll() error {
 	fmt.Printf("Starting %d consumers...\n", len(cm.consumers))
 
+	results := make(chan error, len(cm.consumers))
 	for _, consumer := range cm.consumers {
 		cm.wg.Add(1)
-		go cm.startConsumer(consumer)
+		go cm.startConsumer(consumer, results)
+	}
+
+	var errs []error
+	for range cm.consumers {
+		if err := <-results; err != nil {
+			errs = append(errs, err)
+		}
 	}
+	err := errors.Join(errs...)
 
+	go cm.reportRoles()
+
+	if err != nil {
+		health.SetState("consumers", health.StateFailed, 0, err)
+		fmt.Printf("%d of %d consumers failed to start\n", len(errs), len(cm.consumers))
+		return err
+	}
+
+	health.SetState("consumers", health.StateReady, 0, nil)
 	fmt.Println("All consumers started")
 	return nil
 }
 
-func (cm *ConsumerManager) StopAll() error {
+// StopAll drains every consumer concurrently, bounded by ctx, and only then
+// cancels the context the consumers were started with.
+func (cm *ConsumerManager) StopAll(ctx context.Context) error {
 	fmt.Println("Stopping all consumers...")
 
-	// Cancel the context to signal stop
-	cm.cancel()
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
 
-	// Stop each consumer
 	for _, consumer := range cm.consumers {
-		err := consumer.Stop()
-		if err != nil {
-			fmt.Printf("Error stopping consumer %s: %v\n", consumer.GetName(), err)
-		}
+		wg.Add(1)
+		go func(consumer Consumer) {
+			defer wg.Done()
+
+			err := consumer.Stop(ctx)
+			if err != nil {
+				fmt.Printf("Error stopping consumer %s: %v\n", consumer.GetName(), err)
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(consumer)
 	}
+	wg.Wait()
+
+	// Cancel the context to signal stop
+	cm.cancel()
 
 	// Wait for all goroutines to finish
 	cm.wg.Wait()
 
 	fmt.Println("All consumers stopped")
-	return nil
+	return errors.Join(errs...)
 }
 
 func (cm *ConsumerManager) Wait() {
 	cm.wg.Wait()
 }
 
-func (cm *ConsumerManager) startConsumer(consumer Consumer) {
+// startConsumer starts consumer, sends the outcome to started and then
+// keeps the consumer counted in the wait group until the manager stops
+func (cm *ConsumerManager) startConsumer(consumer Consumer, started chan<- error) {
 	defer cm.wg.Done()
 
 	err := consumer.Start(cm.ctx)
 	if err != nil {
 		fmt.Printf("Error starting consumer %s: %v\n", consumer.GetName(), err)
+		err = fmt.Errorf("consumer %s: %w", consumer.GetName(), err)
+
+		cm.mu.Lock()
+		cm.failed[consumer.GetName()] = err
+		cm.mu.Unlock()
+
+		started <- err
 		return
 	}
+	started <- nil
 
 	// Wait for context cancellation
 	<-cm.ctx.Done()
 	fmt.Printf("Consumer %s context cancelled\n", consumer.GetName())
 }
 
+// Failed returns the start error of every consumer that failed to start, by
+// name
+func (cm *ConsumerManager) Failed() map[string]error {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	failed := make(map[string]error, len(cm.failed))
+	for name, err := range cm.failed {
+		failed[name] = err
+	}
+	return failed
+}
+
 func (cm *ConsumerManager) GetConsumerCount() int {
 	return len(cm.consumers)
 }
@@ -89,3 +158,54 @@ func (cm *ConsumerManager) GetConsumerNames() []string {
 	}
 	return names
 }
+
+// Roles returns the role of every consumer that reports one, by name
+func (cm *ConsumerManager) Roles() map[string]Role {
+	roles := make(map[string]Role)
+	for _, consumer := range cm.consumers {
+		if reporter, ok := consumer.(RoleReporter); ok {
+			roles[consumer.GetName()] = reporter.Role()
+		}
+	}
+	return roles
+}
+
+// IsActive reports whether this instance is currently the active consumer
+// of at least one queue, i.e. it is not purely a standby
+func (cm *ConsumerManager) IsActive() bool {
+	for _, role := range cm.Roles() {
+		if role == RoleActive {
+			return true
+		}
+	}
+	return false
+}
+
+// reportRoles publishes the consumer roles on the readiness endpoint until
+// the manager is stopped
+func (cm *ConsumerManager) reportRoles() {
+	ticker := time.NewTicker(roleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		details := make(map[string]string)
+		for name, role := range cm.Roles() {
+			details[name] = string(role)
+		}
+		for name := range cm.Failed() {
+			details[name] = health.StateFailed
+		}
+		if cm.IsActive() {
+			details["instance"] = string(RoleActive)
+		} else {
+			details["instance"] = string(RoleStandby)
+		}
+		health.SetDetails("consumers", details)
+
+		select {
+		case <-cm.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package consumers
+
+import (
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/dto"
+	"event_service/internal/envelope"
+)
+
+// decodeEvent decodes a delivery into an event, filling envelope fields the
+// body lacks from the AMQP properties when rabbitmq.property_fallback is on.
+// A message decoded before dispatch is not decoded again.
+func decodeEvent(message *envelope.Message) (*dto.GenericEvent, error) {
+	if message.Event != nil || message.DecodeErr != nil {
+		return message.Event, message.DecodeErr
+	}
+
+	event, err := envelope.Decode(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrEventDeserialization, err)
+	}
+
+	if fallback := global.Config.RabbitMQ.PropertyFallback; fallback.Enabled {
+		envelope.FillFromProperties(event, message, fallback)
+	}
+
+	return event, nil
+}
@@ -0,0 +1,59 @@
+package consumers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"event_service/internal/common"
+)
+
+// HandlerActivityLog is the handler that stores events as activity logs
+const HandlerActivityLog = "activity_log"
+
+// HandlerFactory creates the handler a configured consumer feeds
+type HandlerFactory func() MessageHandler
+
+var (
+	handlersMu sync.RWMutex
+	handlers   = map[string]HandlerFactory{
+		HandlerActivityLog: NewActivityLogHandler,
+		HandlerUserErasure: NewUserErasureHandler,
+	}
+)
+
+// RegisterHandler makes a handler available to consumers under name,
+// replacing any handler previously registered with that name
+func RegisterHandler(name string, factory HandlerFactory) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+
+	handlers[name] = factory
+}
+
+// NewHandler creates the handler registered under name
+func NewHandler(name string) (MessageHandler, error) {
+	handlersMu.RLock()
+	factory, exists := handlers[name]
+	handlersMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s (registered: %v)", common.ErrUnknownHandler, name, HandlerNames())
+	}
+
+	return factory(), nil
+}
+
+// HandlerNames returns the registered handler names in a stable order
+func HandlerNames() []string {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+
+	names := make([]string, 0, len(handlers))
+	for name := range handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
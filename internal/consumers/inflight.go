@@ -0,0 +1,97 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// inFlightTracker keeps track of deliveries that have been handed to a
+// handler but not yet acked/nacked. Whoever removes a delivery from the
+// tracker owns it and is responsible for settling it, so a handler that
+// finishes after its delivery was requeued during shutdown will not ack it.
+type inFlightTracker struct {
+	mu         sync.Mutex
+	deliveries map[uint64]amqp091.Delivery
+	wg         sync.WaitGroup
+	requeue    func(message amqp091.Delivery) error
+}
+
+func newInFlightTracker() *inFlightTracker {
+	return &inFlightTracker{
+		deliveries: make(map[uint64]amqp091.Delivery),
+		requeue: func(message amqp091.Delivery) error {
+			return message.Nack(false, true)
+		},
+	}
+}
+
+func (t *inFlightTracker) track(message amqp091.Delivery) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.deliveries[message.DeliveryTag] = message
+	t.wg.Add(1)
+}
+
+// release removes the delivery from the tracker and reports whether the
+// caller still owns it.
+func (t *inFlightTracker) release(deliveryTag uint64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.deliveries[deliveryTag]; !exists {
+		return false
+	}
+
+	delete(t.deliveries, deliveryTag)
+	t.wg.Done()
+	return true
+}
+
+// count returns how many deliveries are still being handled
+func (t *inFlightTracker) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.deliveries)
+}
+
+// wait blocks until every tracked delivery has been released or ctx is done.
+func (t *inFlightTracker) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// requeueAll hands every tracked delivery back to the broker (nack with
+// requeue unless the consumer says otherwise) and returns how many were
+// requeued.
+func (t *inFlightTracker) requeueAll() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	requeued := 0
+	for deliveryTag, message := range t.deliveries {
+		if err := t.requeue(message); err != nil {
+			fmt.Printf("Error requeueing in-flight message %d: %v\n", deliveryTag, err)
+		} else {
+			requeued++
+		}
+		delete(t.deliveries, deliveryTag)
+		t.wg.Done()
+	}
+
+	return requeued
+}
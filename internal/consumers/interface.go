@@ -1,11 +1,31 @@
 package consumers
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"event_service/internal/envelope"
+)
 
 type Consumer interface {
 	Start(ctx context.Context) error
 
-	Stop() error
+	// Stop stops receiving new deliveries and drains in-flight ones until
+	// ctx is done.
+	Stop(ctx context.Context) error
 
 	GetName() string
 }
+
+// MessageHandler processes a single delivery. A returned error makes the
+// consumer retry or dead-letter the message.
+type MessageHandler interface {
+	Handle(ctx context.Context, message *envelope.Message) error
+}
+
+// TimedHandler is a MessageHandler that needs another processing deadline
+// than the consumer's default, e.g. because one message starts a long run
+type TimedHandler interface {
+	MessageHandler
+	Timeout() time.Duration
+}
@@ -0,0 +1,98 @@
+package consumers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"event_service/internal/dto"
+	"event_service/internal/envelope"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// dispatched is a delivery on its way to a worker together with the
+// message its handler will see. decoded is closed once the message has been
+// decoded for its ordering key, or right away when no key is needed.
+type dispatched struct {
+	delivery amqp091.Delivery
+	message  *envelope.Message
+	decoded  chan struct{}
+}
+
+// ordered reports whether deliveries need decoding before dispatch to find
+// their ordering key
+func (c *queueConsumer) ordered() bool {
+	return c.workerCount > 1 && len(c.orderingKeys) > 0
+}
+
+// predecode decodes d's message once for its ordering key. It runs off the
+// dispatcher goroutine; the handler reuses the result.
+func predecode(d *dispatched) {
+	defer close(d.decoded)
+	d.message.Event, d.message.DecodeErr = decodeEvent(d.message)
+}
+
+// partition picks the worker a delivery is handed to. Deliveries with the
+// same ordering key always land on the same worker and are therefore handled
+// in the order they were received; deliveries without a key are spread
+// across workers.
+func (c *queueConsumer) partition(d *dispatched) int {
+	if c.workerCount <= 1 {
+		return 0
+	}
+
+	key := extractOrderingKey(d.message.Event, c.orderingKeys)
+	if key == "" {
+		return int(d.delivery.DeliveryTag % uint64(c.workerCount))
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(c.workerCount))
+}
+
+// extractOrderingKey returns the value of the first of paths present in the
+// decoded event, whatever format it arrived in; "" when the event could not
+// be decoded. Paths are dot-separated envelope field names, e.g.
+// "payload.workspaceId".
+func extractOrderingKey(event *dto.GenericEvent, paths []string) string {
+	if event == nil || len(paths) == 0 {
+		return ""
+	}
+
+	document := map[string]interface{}{
+		"eventId":       event.EventID,
+		"topic":         event.Topic,
+		"sourceService": event.SourceService,
+		"subject":       event.Subject,
+		"payload":       event.Payload,
+		"extensions":    event.Extensions,
+	}
+
+	for _, path := range paths {
+		if value, ok := lookupPath(document, path); ok && value != "" {
+			return fmt.Sprint(value)
+		}
+	}
+
+	return ""
+}
+
+func lookupPath(document map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = document
+
+	for _, field := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		current, ok = object[field]
+		if !ok || current == nil {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
@@ -0,0 +1,530 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/breaker"
+	"event_service/internal/common"
+	"event_service/internal/envelope"
+	"event_service/internal/repo"
+	"event_service/internal/topology"
+	"event_service/pkg/setting"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// defaultHandleTimeout bounds the handling of one delivery, unless the
+// handler is a TimedHandler
+const defaultHandleTimeout = 30 * time.Second
+
+// queueConsumer consumes one configured queue and feeds deliveries to its
+// handler. Each consumer has its own channel, retry and dead letter path.
+type queueConsumer struct {
+	name            string
+	config          setting.Consumer
+	handler         MessageHandler
+	breaker         *breaker.Breaker
+	channel         *amqp091.Channel
+	queue           string
+	prefetch        int
+	workerCount     int
+	orderingKeys    []string
+	mu              sync.Mutex
+	isRunning       bool
+	stopChannel     chan bool
+	ctx             context.Context
+	subscription    *subscription
+	subscriptionSeq int
+	inFlight        *inFlightTracker
+	stream          *streamOffsets // nil unless the queue is a stream
+	role            Role
+	management      *topology.ManagementClient // nil unless single-active-consumer and configured
+}
+
+// NewQueueConsumer creates a consumer for cfg that passes every delivery
+// to handler
+func NewQueueConsumer(cfg setting.Consumer, handler MessageHandler) Consumer {
+	c := &queueConsumer{
+		name:        fmt.Sprintf("QueueConsumer[%s]", cfg.Name),
+		config:      cfg,
+		handler:     handler,
+		breaker:     global.MongoBreaker,
+		stopChannel: make(chan bool),
+		inFlight:    newInFlightTracker(),
+	}
+
+	rabbitCfg := global.Config.RabbitMQ
+	if cfg.SingleActiveConsumer && rabbitCfg.ManagementURL != "" {
+		c.management = topology.NewManagementClient(rabbitCfg.ManagementURL, "/", rabbitCfg.User, rabbitCfg.Password)
+	}
+
+	if c.isStream() {
+		var repository repo.StreamOffsetRepository
+		if cfg.StreamOffset == "stored" {
+			repository = repo.NewStreamOffsetRepository()
+		}
+		c.stream = newStreamOffsets(repository)
+
+		// Streams can't requeue; an unfinished delivery is acked and picked
+		// up again by the next subscription, which resumes from the
+		// committed offset
+		c.inFlight.requeue = func(message amqp091.Delivery) error {
+			return message.Ack(false)
+		}
+	}
+
+	return c
+}
+
+func (c *queueConsumer) GetName() string {
+	return c.name
+}
+
+func (c *queueConsumer) Start(ctx context.Context) error {
+	fmt.Printf("Starting %s...\n", c.name)
+
+	c.channel = global.RabbitChannels[c.config.Name]
+	c.queue = c.config.Queue
+	c.prefetch = c.config.PrefetchCount
+	if c.prefetch <= 0 {
+		c.prefetch = 1
+	}
+	c.workerCount = c.config.WorkerCount
+	if c.workerCount <= 0 {
+		c.workerCount = 1
+	}
+	c.orderingKeys = c.config.OrderingKeys
+
+	if c.prefetch < c.workerCount {
+		fmt.Printf("Warning: %s prefetch %d is lower than worker count %d, some workers will stay idle\n",
+			c.name, c.prefetch, c.workerCount)
+	}
+
+	if c.channel == nil {
+		return fmt.Errorf("RabbitMQ channel is not initialized")
+	}
+
+	if c.stream != nil {
+		loadCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err := c.stream.load(loadCtx, c.config.Name)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to load stream offset: %w", err)
+		}
+
+		go c.stream.commitLoop(c.config.Name, c.stopChannel)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ctx = ctx
+
+	// Don't pull from the queue while MongoDB writes are being rejected
+	breakerState := breaker.StateClosed
+	if c.breaker != nil {
+		breakerState = c.breaker.State()
+	}
+
+	switch breakerState {
+	case breaker.StateOpen:
+		fmt.Printf("%s starting paused: MongoDB circuit breaker is open\n", c.name)
+	case breaker.StateHalfOpen:
+		if err := c.subscribe(1); err != nil {
+			return err
+		}
+	default:
+		if err := c.subscribe(c.prefetch); err != nil {
+			return err
+		}
+	}
+
+	if c.breaker != nil {
+		c.breaker.OnStateChange(c.onBreakerStateChange)
+	}
+
+	c.isRunning = true
+	if c.config.SingleActiveConsumer {
+		go c.monitorRole()
+	}
+	fmt.Printf("%s started successfully\n", c.name)
+
+	return nil
+}
+
+// Stop cancels the broker subscription so no new deliveries arrive, then
+// waits for in-flight messages to finish until ctx is done. Anything still
+// unfinished at the deadline is nacked with requeue.
+func (c *queueConsumer) Stop(ctx context.Context) error {
+	fmt.Printf("Stopping %s...\n", c.name)
+
+	c.mu.Lock()
+	if !c.isRunning {
+		c.mu.Unlock()
+		return nil
+	}
+
+	c.isRunning = false
+	close(c.stopChannel)
+	c.setRole(RoleStandby)
+
+	sub := c.subscription
+	c.subscription = nil
+	c.mu.Unlock()
+
+	if sub != nil {
+		c.unsubscribe(sub, false)
+
+		select {
+		case <-sub.done:
+		case <-ctx.Done():
+		}
+	}
+
+	if pending := c.inFlight.count(); pending > 0 {
+		fmt.Printf("%s draining %d in-flight messages\n", c.name, pending)
+	}
+	if err := c.inFlight.wait(ctx); err != nil {
+		if sub != nil {
+			sub.cancel()
+		}
+		requeued := c.inFlight.requeueAll()
+		c.saveStreamOffset(context.Background())
+		fmt.Printf("%s drain deadline exceeded, requeued %d in-flight messages\n", c.name, requeued)
+		return fmt.Errorf("%s drain deadline exceeded: %d in-flight messages requeued", c.name, requeued)
+	}
+
+	if sub != nil {
+		sub.cancel()
+	}
+	c.saveStreamOffset(ctx)
+	fmt.Printf("%s stopped\n", c.name)
+	return nil
+}
+
+func (c *queueConsumer) saveStreamOffset(ctx context.Context) {
+	if c.stream == nil {
+		return
+	}
+
+	if err := c.stream.save(ctx, c.config.Name); err != nil {
+		fmt.Printf("Error saving stream offset for %s: %v\n", c.name, err)
+	}
+}
+
+// onBreakerStateChange pauses consumption while the MongoDB circuit breaker
+// is open, resumes with a single-message prefetch while it is half-open so
+// the next delivery acts as the probe write, and restores the configured
+// prefetch once it closes.
+func (c *queueConsumer) onBreakerStateChange(state breaker.State) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.isRunning {
+		return
+	}
+
+	switch state {
+	case breaker.StateOpen:
+		if sub := c.subscription; sub != nil {
+			fmt.Printf("%s pausing: MongoDB circuit breaker is open\n", c.name)
+			c.subscription = nil
+			c.unsubscribe(sub, true)
+			c.setRole(RoleStandby)
+		}
+
+	case breaker.StateHalfOpen:
+		if c.subscription == nil {
+			fmt.Printf("%s resuming with prefetch 1 to probe MongoDB\n", c.name)
+			if err := c.subscribe(1); err != nil {
+				fmt.Printf("Error resuming %s: %v\n", c.name, err)
+			}
+		}
+
+	case breaker.StateClosed:
+		if sub := c.subscription; sub != nil && sub.prefetch != c.prefetch {
+			c.subscription = nil
+			c.unsubscribe(sub, false)
+		}
+		if c.subscription == nil {
+			fmt.Printf("%s resuming with prefetch %d\n", c.name, c.prefetch)
+			if err := c.subscribe(c.prefetch); err != nil {
+				fmt.Printf("Error resuming %s: %v\n", c.name, err)
+			}
+		}
+	}
+}
+
+func (c *queueConsumer) handleMessage(ctx context.Context, message amqp091.Delivery, handled *envelope.Message) {
+	fmt.Printf("Received message with routing key: %s\n", message.RoutingKey)
+
+	timeout := defaultHandleTimeout
+	if timed, ok := c.handler.(TimedHandler); ok {
+		timeout = timed.Timeout()
+	}
+	processCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := c.handler.Handle(processCtx, handled)
+
+	if !c.inFlight.release(message.DeliveryTag) {
+		fmt.Printf("Message %d was already requeued, skipping acknowledgement\n", message.DeliveryTag)
+		return
+	}
+
+	if errors.Is(err, common.ErrCircuitOpen) {
+		// MongoDB writes are suspended; hand the message back without
+		// counting it as a retry
+		c.requeueMessage(message)
+		return
+	}
+
+	defer c.streamCompleted(message)
+
+	if err != nil {
+		fmt.Printf("Error processing message: %v\n", err)
+
+		if c.shouldRetry(message) {
+			c.retryMessage(message, err)
+		} else {
+			c.rejectMessage(message, err)
+		}
+		return
+	}
+
+	// Acknowledge successful processing
+	err = message.Ack(false)
+	if err != nil {
+		fmt.Printf("Error acknowledging message: %v\n", err)
+	} else {
+		fmt.Printf("Message processed and acknowledged successfully\n")
+	}
+}
+
+func (c *queueConsumer) isStream() bool {
+	return c.config.QueueType == "stream"
+}
+
+// nativeRedelivery reports whether retries are left to the broker: a quorum
+// queue with a delivery limit redelivers requeued messages itself and
+// dead-letters them once the limit is exceeded
+func (c *queueConsumer) nativeRedelivery() bool {
+	return c.config.QueueType == "quorum" && c.config.DeliveryLimit > 0
+}
+
+func (c *queueConsumer) shouldRetry(message amqp091.Delivery) bool {
+	switch {
+	case c.isStream():
+		// A stream can't redeliver a single message and republishing would
+		// append a duplicate, so failures go to the dead letter queue
+		return false
+	case c.nativeRedelivery():
+		return c.getDeliveryCount(message) < c.config.DeliveryLimit
+	}
+
+	retryCount := c.getRetryCount(message)
+	return retryCount < c.config.RetryAttempts
+}
+
+// getDeliveryCount returns how many times a quorum queue has already
+// delivered the message
+func (c *queueConsumer) getDeliveryCount(message amqp091.Delivery) int {
+	switch count := message.Headers[common.HeaderDeliveryCount].(type) {
+	case int64:
+		return int(count)
+	case int32:
+		return int(count)
+	default:
+		return 0
+	}
+}
+
+func (c *queueConsumer) getRetryCount(message amqp091.Delivery) int {
+	if message.Headers == nil {
+		return 0
+	}
+
+	if retryCount, exists := message.Headers[common.HeaderRetryCount]; exists {
+		if count, ok := retryCount.(int32); ok {
+			return int(count)
+		}
+	}
+
+	return 0
+}
+
+// originExchange returns the exchange the event was originally published to.
+// Retries go straight back to the consumer's queue, so it is recorded in a
+// header for re-routing messages out of the dead letter queue.
+func (c *queueConsumer) originExchange(message amqp091.Delivery) string {
+	if origin, ok := message.Headers[common.HeaderOriginExchange].(string); ok && origin != "" {
+		return origin
+	}
+
+	if message.Exchange != "" {
+		return message.Exchange
+	}
+
+	return c.config.Exchange
+}
+
+// recordOrigin keeps the original routing key, user-id and exchange of a
+// message in the headers it is republished with, since the republish
+// replaces them
+func (c *queueConsumer) recordOrigin(headers amqp091.Table, message amqp091.Delivery) {
+	routingKey, userID := publishedBy(envelope.FromDelivery(message))
+	headers[common.HeaderOriginalRoutingKey] = routingKey
+	headers[common.HeaderOriginalUserID] = userID
+	headers[common.HeaderOriginExchange] = c.originExchange(message)
+}
+
+func (c *queueConsumer) retryMessage(message amqp091.Delivery, processingError error) {
+	if c.nativeRedelivery() {
+		fmt.Printf("Requeueing message for redelivery (delivery %d of %d): %v\n",
+			c.getDeliveryCount(message)+1, c.config.DeliveryLimit+1, processingError)
+		c.requeueMessage(message)
+		return
+	}
+
+	retryCount := c.getRetryCount(message) + 1
+	retryDelay := c.config.RetryDelaySeconds
+
+	fmt.Printf("Retrying message via %s in %ds (attempt %d): %v\n", c.config.RetryQueue, retryDelay, retryCount, processingError)
+
+	headers := make(amqp091.Table)
+	if message.Headers != nil {
+		headers = message.Headers
+	}
+	c.recordOrigin(headers, message)
+	headers[common.HeaderRetryCount] = int32(retryCount)
+	headers[common.HeaderRetryReason] = processingError.Error()
+
+	// Through the default exchange to this consumer's retry queue, which
+	// dead-letters the message back to c.queue once the delay has passed.
+	// Republishing to the source exchange would also hand a copy to every
+	// other queue bound to it.
+	err := c.channel.Publish(
+		"",                  // default exchange
+		c.config.RetryQueue, // routing key
+		false,               // mandatory
+		false,               // immediate
+		amqp091.Publishing{
+			ContentType:     message.ContentType,
+			ContentEncoding: message.ContentEncoding,
+			MessageId:       message.MessageId,
+			CorrelationId:   message.CorrelationId,
+			Timestamp:       message.Timestamp,
+			AppId:           message.AppId,
+			Type:            message.Type,
+			UserId:          global.Config.RabbitMQ.User,
+			Body:            message.Body,
+			Headers:         headers,
+			Expiration:      fmt.Sprintf("%d000", retryDelay), // milliseconds
+		},
+	)
+
+	if err != nil {
+		fmt.Printf("Error publishing retry message: %v\n", err)
+		c.rejectMessage(message, err)
+		return
+	}
+
+	// Ack the original message
+	err = message.Ack(false)
+	if err != nil {
+		fmt.Printf("Error acknowledging retry message: %v\n", err)
+	}
+}
+
+func (c *queueConsumer) rejectMessage(message amqp091.Delivery, processingError error) {
+	fmt.Printf("Rejecting message after max retries: %v\n", processingError)
+
+	// Log the failed message for manual investigation
+	c.logFailedMessage(message, processingError)
+
+	if c.config.DeadLetterQueue != "" {
+		if c.deadLetterMessage(message, processingError) {
+			return
+		}
+	}
+
+	// Reject the message (no dead letter queue configured, or publishing to
+	// it failed). Streams don't support reject, so the message is acked and
+	// stays in the stream.
+	if c.isStream() {
+		if err := message.Ack(false); err != nil {
+			fmt.Printf("Error acknowledging rejected stream message: %v\n", err)
+		}
+		return
+	}
+
+	err := message.Reject(false) // false = don't requeue
+	if err != nil {
+		fmt.Printf("Error rejecting message: %v\n", err)
+	}
+}
+
+// deadLetterMessage moves the message to the consumer's dead letter queue and
+// acks the original. It reports whether the message was dead-lettered.
+func (c *queueConsumer) deadLetterMessage(message amqp091.Delivery, processingError error) bool {
+	headers := make(amqp091.Table)
+	for key, value := range message.Headers {
+		headers[key] = value
+	}
+	c.recordOrigin(headers, message)
+	headers[common.HeaderDeadLetterReason] = processingError.Error()
+
+	err := c.channel.Publish(
+		"",                       // default exchange
+		c.config.DeadLetterQueue, // routing key
+		false,                    // mandatory
+		false,                    // immediate
+		amqp091.Publishing{
+			ContentType:     message.ContentType,
+			ContentEncoding: message.ContentEncoding,
+			MessageId:       message.MessageId,
+			CorrelationId:   message.CorrelationId,
+			Timestamp:       message.Timestamp,
+			AppId:           message.AppId,
+			Type:            message.Type,
+			UserId:          global.Config.RabbitMQ.User,
+			DeliveryMode:    amqp091.Persistent,
+			Body:            message.Body,
+			Headers:         headers,
+		},
+	)
+	if err != nil {
+		fmt.Printf("Error publishing to dead letter queue %s: %v\n", c.config.DeadLetterQueue, err)
+		return false
+	}
+
+	err = message.Ack(false)
+	if err != nil {
+		fmt.Printf("Error acknowledging dead-lettered message: %v\n", err)
+	}
+
+	fmt.Printf("Message moved to dead letter queue %s\n", c.config.DeadLetterQueue)
+	return true
+}
+
+func (c *queueConsumer) requeueMessage(message amqp091.Delivery) {
+	err := c.inFlight.requeue(message)
+	if err != nil {
+		fmt.Printf("Error requeueing message: %v\n", err)
+	}
+}
+
+func (c *queueConsumer) logFailedMessage(message amqp091.Delivery, processingError error) {
+	fmt.Printf("FAILED MESSAGE LOG:\n")
+	fmt.Printf("  Consumer: %s\n", c.config.Name)
+	fmt.Printf("  Routing Key: %s\n", message.RoutingKey)
+	fmt.Printf("  Error: %v\n", processingError)
+	fmt.Printf("  Body: %s\n", string(message.Body))
+	fmt.Printf("  Headers: %+v\n", message.Headers)
+	fmt.Printf("  Retry Count: %d\n", c.getRetryCount(message))
+}
@@ -0,0 +1,119 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/internal/metrics"
+)
+
+// Role of a consumer on its queue. Only one subscriber of a
+// single-active-consumer queue receives deliveries; the rest are standbys.
+type Role string
+
+const (
+	RoleActive  Role = "active"
+	RoleStandby Role = "standby"
+	RoleUnknown Role = "unknown"
+)
+
+const roleCheckInterval = 5 * time.Second
+
+// RoleReporter is implemented by consumers that know whether they are
+// currently receiving deliveries
+type RoleReporter interface {
+	Role() Role
+}
+
+var consumerActive = metrics.NewGauge("event_service_consumer_active",
+	"1 if this instance is the active consumer of the queue, 0 if standby", "consumer")
+
+func (c *queueConsumer) Role() Role {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.isRunning || c.subscription == nil {
+		return RoleStandby
+	}
+	if !c.config.SingleActiveConsumer {
+		return RoleActive
+	}
+	return c.role
+}
+
+// setRole must be called with c.mu held
+func (c *queueConsumer) setRole(role Role) {
+	if c.role == role {
+		return
+	}
+
+	fmt.Printf("%s is now %s on queue %s\n", c.name, role, c.queue)
+	c.role = role
+
+	value := 0.0
+	if role == RoleActive {
+		value = 1
+	}
+	consumerActive.Set(value, c.config.Name)
+}
+
+// markActive records that a delivery arrived on sub, which only happens to
+// the active consumer of a single-active-consumer queue
+func (c *queueConsumer) markActive(sub *subscription) {
+	if !c.config.SingleActiveConsumer {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.subscription == sub {
+		c.setRole(RoleActive)
+	}
+}
+
+// monitorRole asks the management API which subscription the broker has
+// made active, since AMQP 0-9-1 doesn't tell a standby it was promoted
+// until a delivery arrives, nor an active consumer that it was demoted.
+func (c *queueConsumer) monitorRole() {
+	ticker := time.NewTicker(roleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopChannel:
+			return
+		case <-ticker.C:
+			c.checkRole()
+		}
+	}
+}
+
+func (c *queueConsumer) checkRole() {
+	if c.management == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tag, err := c.management.SingleActiveConsumerTag(ctx, c.queue)
+	if err != nil {
+		fmt.Printf("Error checking active consumer of %s: %v\n", c.queue, err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.isRunning {
+		return
+	}
+
+	if c.subscription != nil && c.subscription.tag == tag {
+		c.setRole(RoleActive)
+	} else {
+		c.setRole(RoleStandby)
+	}
+}
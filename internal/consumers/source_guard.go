@@ -0,0 +1,84 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/dto"
+	"event_service/internal/envelope"
+	"event_service/internal/services"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// sourceGuard quarantines events whose claimed topic or source service
+// doesn't match their delivery, before a handler acts on them
+type sourceGuard struct {
+	handler    string
+	verifier   *services.SourceVerifier
+	quarantine services.QuarantineService // nil when verification is disabled
+}
+
+func newSourceGuard(handler string) *sourceGuard {
+	// The configuration was validated when it was loaded
+	verifier, err := services.NewSourceVerifier(global.Config.Verification)
+	if err != nil {
+		panic(fmt.Errorf("%w: %v", common.ErrConfigValidation, err))
+	}
+
+	guard := &sourceGuard{handler: handler, verifier: verifier}
+	if global.Config.Verification.Enabled {
+		guard.quarantine = services.NewQuarantineService()
+	}
+	return guard
+}
+
+// admit reports whether the event may be processed. Rejected events are
+// quarantined; an error means quarantining failed and the message should be
+// retried.
+func (g *sourceGuard) admit(ctx context.Context, message *envelope.Message, event *dto.GenericEvent) (bool, error) {
+	if g.quarantine == nil {
+		return true, nil
+	}
+
+	violations := g.verifier.Verify(event, message.RoutingKey, message.UserID)
+	if len(violations) == 0 {
+		return true, nil
+	}
+
+	if err := g.quarantine.Quarantine(ctx, event, g.handler, message.RoutingKey, message.UserID, violations); err != nil {
+		return false, fmt.Errorf("%w: %w", common.ErrEventProcessing, err)
+	}
+	return false, nil
+}
+
+// newMessage builds the message handlers see from a delivery, with the
+// routing key and user-id of the original publish
+func newMessage(delivery amqp091.Delivery) *envelope.Message {
+	message := envelope.FromDelivery(delivery)
+	message.RoutingKey, message.UserID = publishedBy(message)
+	return message
+}
+
+// publishedBy returns the routing key and AMQP user-id of the original
+// publish. Retries and dead letters are republished by this service under
+// its own user with the originals in headers; those headers are only
+// trusted on messages this service published, since the broker rejects a
+// user-id other than the publisher's. The configuration is checked so that
+// no verified producer shares the service's user.
+func publishedBy(message *envelope.Message) (string, string) {
+	routingKey, userID := message.RoutingKey, message.UserID
+	if userID == "" || userID != global.Config.RabbitMQ.User {
+		return routingKey, userID
+	}
+
+	if original, ok := message.Headers[common.HeaderOriginalRoutingKey].(string); ok && original != "" {
+		routingKey = original
+	}
+	if original, ok := message.Headers[common.HeaderOriginalUserID].(string); ok {
+		userID = original
+	}
+	return routingKey, userID
+}
@@ -0,0 +1,186 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"event_service/internal/common"
+	"event_service/internal/repo"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+const streamOffsetCommitInterval = time.Second
+
+// streamOffsets tracks how far a stream consumer has got. Deliveries arrive
+// in offset order but workers may finish them out of order, so the committed
+// offset only advances past offsets whose processing has finished. A
+// resubscription resumes right after the committed offset.
+type streamOffsets struct {
+	mu         sync.Mutex
+	pending    []int64
+	done       map[int64]bool
+	committed  int64
+	hasOffset  bool
+	saved      int64
+	repository repo.StreamOffsetRepository // nil unless offsets are stored
+}
+
+func newStreamOffsets(repository repo.StreamOffsetRepository) *streamOffsets {
+	return &streamOffsets{
+		done:       make(map[int64]bool),
+		saved:      -1,
+		repository: repository,
+	}
+}
+
+// load restores the committed offset from MongoDB when offsets are stored
+func (s *streamOffsets) load(ctx context.Context, consumer string) error {
+	if s.repository == nil {
+		return nil
+	}
+
+	offset, found, err := s.repository.Get(ctx, consumer)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if found {
+		s.committed = offset
+		s.hasOffset = true
+		s.saved = offset
+	}
+	return nil
+}
+
+// resumeFrom returns the offset to resume consuming from, if any
+func (s *streamOffsets) resumeFrom() (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.committed + 1, s.hasOffset
+}
+
+// reset forgets deliveries of a previous subscription
+func (s *streamOffsets) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = s.pending[:0]
+	s.done = make(map[int64]bool)
+}
+
+func (s *streamOffsets) dispatched(offset int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = append(s.pending, offset)
+}
+
+func (s *streamOffsets) completed(offset int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done[offset] = true
+	for len(s.pending) > 0 && s.done[s.pending[0]] {
+		delete(s.done, s.pending[0])
+		s.committed = s.pending[0]
+		s.hasOffset = true
+		s.pending = s.pending[1:]
+	}
+}
+
+// save persists the committed offset if it changed since the last save
+func (s *streamOffsets) save(ctx context.Context, consumer string) error {
+	if s.repository == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	offset, changed := s.committed, s.hasOffset && s.committed != s.saved
+	s.mu.Unlock()
+
+	if !changed {
+		return nil
+	}
+
+	if err := s.repository.Save(ctx, consumer, offset); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.saved = offset
+	s.mu.Unlock()
+	return nil
+}
+
+// commitLoop saves the committed offset periodically until stop is closed
+func (s *streamOffsets) commitLoop(consumer string, stop <-chan bool) {
+	ticker := time.NewTicker(streamOffsetCommitInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := s.save(ctx, consumer); err != nil {
+				fmt.Printf("Error saving stream offset for %s: %v\n", consumer, err)
+			}
+			cancel()
+		}
+	}
+}
+
+// streamOffset returns the offset of a stream delivery
+func streamOffset(message amqp091.Delivery) (int64, bool) {
+	switch offset := message.Headers[common.HeaderStreamOffset].(type) {
+	case int64:
+		return offset, true
+	case int32:
+		return int64(offset), true
+	default:
+		return 0, false
+	}
+}
+
+// streamCompleted marks a settled stream delivery as processed
+func (c *queueConsumer) streamCompleted(message amqp091.Delivery) {
+	if c.stream == nil {
+		return
+	}
+
+	if offset, ok := streamOffset(message); ok {
+		c.stream.completed(offset)
+	}
+}
+
+// streamConsumeArguments returns the x-stream-offset a stream subscription
+// starts from: right after the committed offset when there is one, else the
+// configured starting point
+func (c *queueConsumer) streamConsumeArguments() (amqp091.Table, error) {
+	if offset, ok := c.stream.resumeFrom(); ok {
+		return amqp091.Table{"x-stream-offset": offset}, nil
+	}
+
+	switch c.config.StreamOffset {
+	case "", "first", "stored":
+		return amqp091.Table{"x-stream-offset": "first"}, nil
+	case "last", "next":
+		return amqp091.Table{"x-stream-offset": c.config.StreamOffset}, nil
+	case "timestamp":
+		at, err := time.Parse(time.RFC3339, c.config.StreamOffsetTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stream offset timestamp %q: %v", c.config.StreamOffsetTimestamp, err)
+		}
+		return amqp091.Table{"x-stream-offset": at}, nil
+	default:
+		return nil, fmt.Errorf("unknown stream offset %q", c.config.StreamOffset)
+	}
+}
@@ -0,0 +1,203 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"event_service/global"
+	"event_service/internal/common"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// subscription is one basic.consume on the consumer's queue. A consumer may
+// cancel its subscription and open a new one (e.g. to pause while MongoDB is
+// unavailable or to change the prefetch), so each has its own tag, handler
+// context and delivery loop.
+type subscription struct {
+	tag      string
+	prefetch int
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopping chan struct{}
+	done     chan struct{}
+}
+
+// subscribe must be called with c.mu held
+func (c *queueConsumer) subscribe(prefetch int) error {
+	err := c.channel.Qos(
+		prefetch, // prefetch count
+		0,        // prefetch size
+		false,    // global
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set QoS: %v", err)
+	}
+
+	var args amqp091.Table
+	if c.stream != nil {
+		c.stream.reset()
+
+		args, err = c.streamConsumeArguments()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tags must differ between replicas to tell which one a
+	// single-active-consumer queue has made active
+	c.subscriptionSeq++
+	tag := fmt.Sprintf("%s-%s-%d", c.name, global.InstanceID, c.subscriptionSeq)
+
+	messages, err := c.channel.Consume(
+		c.queue, // queue
+		tag,     // consumer
+		false,   // auto-ack (we'll ack manually)
+		false,   // exclusive
+		false,   // no-local
+		false,   // no-wait
+		args,    // args
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrRabbitConsume, err)
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	sub := &subscription{
+		tag:      tag,
+		prefetch: prefetch,
+		ctx:      ctx,
+		cancel:   cancel,
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	c.subscription = sub
+
+	if c.config.SingleActiveConsumer {
+		// Unknown until the first delivery or management API check
+		c.setRole(RoleUnknown)
+		go c.checkRole()
+	} else {
+		c.setRole(RoleActive)
+	}
+
+	go c.processMessages(sub, messages)
+	return nil
+}
+
+// unsubscribe cancels the broker subscription without waiting for its
+// delivery loop. With abort set, in-flight handlers are cancelled and their
+// deliveries requeued immediately instead of being allowed to finish.
+func (c *queueConsumer) unsubscribe(sub *subscription, abort bool) {
+	close(sub.stopping)
+
+	if c.channel != nil {
+		err := c.channel.Cancel(sub.tag, false)
+		if err != nil {
+			fmt.Printf("Error canceling consumer %s: %v\n", sub.tag, err)
+		}
+	}
+
+	if abort {
+		sub.cancel()
+		requeued := c.inFlight.requeueAll()
+		if requeued > 0 {
+			fmt.Printf("%s requeued %d in-flight messages\n", c.name, requeued)
+		}
+	}
+}
+
+// processMessages dispatches deliveries to the consumer's workers, each of
+// which handles its share sequentially. When deliveries are partitioned by
+// ordering key, they are decoded concurrently and a router hands them to
+// the workers in the order they were received.
+func (c *queueConsumer) processMessages(sub *subscription, messages <-chan amqp091.Delivery) {
+	defer close(sub.done)
+	defer sub.cancel()
+
+	workerCount := c.workerCount
+	if workerCount <= 0 {
+		workerCount = 1
+	}
+
+	var wg sync.WaitGroup
+	workers := make([]chan *dispatched, workerCount)
+	for i := range workers {
+		workers[i] = make(chan *dispatched, sub.prefetch)
+
+		wg.Add(1)
+		go func(queue <-chan *dispatched) {
+			defer wg.Done()
+			for d := range queue {
+				c.work(sub, d)
+			}
+		}(workers[i])
+	}
+
+	routed := make(chan *dispatched, sub.prefetch)
+	routerDone := make(chan struct{})
+	go func() {
+		defer close(routerDone)
+		for d := range routed {
+			<-d.decoded
+			workers[c.partition(d)] <- d
+		}
+	}()
+
+	decodeSlots := make(chan struct{}, workerCount)
+	for message := range messages {
+		select {
+		case <-sub.stopping:
+			// Deliveries prefetched before the cancel went through are handed
+			// back to the broker instead of being processed
+			c.requeueMessage(message)
+			continue
+		default:
+		}
+
+		c.markActive(sub)
+		c.inFlight.track(message)
+		if c.stream != nil {
+			if offset, ok := streamOffset(message); ok {
+				c.stream.dispatched(offset)
+			}
+		}
+
+		d := &dispatched{delivery: message, message: newMessage(message), decoded: make(chan struct{})}
+		if c.ordered() {
+			decodeSlots <- struct{}{}
+			go func() {
+				defer func() { <-decodeSlots }()
+				predecode(d)
+			}()
+		} else {
+			close(d.decoded)
+		}
+		routed <- d
+	}
+
+	close(routed)
+	<-routerDone
+	for _, queue := range workers {
+		close(queue)
+	}
+	wg.Wait()
+
+	fmt.Printf("%s message channel closed\n", sub.tag)
+}
+
+func (c *queueConsumer) work(sub *subscription, d *dispatched) {
+	select {
+	case <-sub.stopping:
+		// Queued behind another delivery of the same key when the
+		// subscription was cancelled; let the broker redeliver it
+		if c.inFlight.release(d.delivery.DeliveryTag) {
+			c.requeueMessage(d.delivery)
+		}
+		return
+	default:
+	}
+
+	c.handleMessage(sub.ctx, d.delivery, d.message)
+}
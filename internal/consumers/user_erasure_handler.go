@@ -0,0 +1,84 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/envelope"
+	"event_service/internal/services"
+)
+
+// HandlerUserErasure is the handler that erases a deleted user's activity
+// logs
+const HandlerUserErasure = "user_erasure"
+
+// defaultErasureTimeout gives a user with many logs time to be erased in
+// one delivery instead of spending the retry budget on 30 second slices
+const defaultErasureTimeout = 15 * time.Minute
+
+// userErasureHandler anonymizes or deletes the activity logs of users named
+// in user.deleted.log events. Other topics are acknowledged and ignored.
+type userErasureHandler struct {
+	erasureService services.ErasureService
+	userIDPath     string
+	timeout        time.Duration
+	guard          *sourceGuard
+}
+
+func NewUserErasureHandler() MessageHandler {
+	userIDPath := global.Config.Erasure.UserIDPath
+	if userIDPath == "" {
+		userIDPath = "userId"
+	}
+
+	timeout := defaultErasureTimeout
+	if seconds := global.Config.Erasure.TimeoutSeconds; seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	return &userErasureHandler{
+		erasureService: services.NewErasureService(),
+		userIDPath:     userIDPath,
+		timeout:        timeout,
+		guard:          newSourceGuard(HandlerUserErasure),
+	}
+}
+
+// Timeout lets an erasure run past the consumer's default deadline
+func (h *userErasureHandler) Timeout() time.Duration {
+	return h.timeout
+}
+
+func (h *userErasureHandler) Handle(ctx context.Context, message *envelope.Message) error {
+	event, err := decodeEvent(message)
+	if err != nil {
+		return err
+	}
+
+	if event.Topic != common.UserDeletedLog {
+		return nil
+	}
+
+	// A forged deletion would erase a real user's history
+	if admitted, err := h.guard.admit(ctx, message, event); !admitted {
+		return err
+	}
+
+	value, ok := lookupPath(event.Payload, h.userIDPath)
+	userID, isString := value.(string)
+	if !ok || !isString || userID == "" {
+		return fmt.Errorf("%w: event %s has no user id at payload.%s", common.ErrEventValidation, event.EventID, h.userIDPath)
+	}
+
+	fmt.Printf("Erasing activity logs for deleted user (event %s)\n", event.EventID)
+
+	// The mode comes from the erasure configuration
+	if _, err := h.erasureService.EraseUser(ctx, userID, "", "event:"+event.EventID); err != nil {
+		return fmt.Errorf("%w: %w", common.ErrEventProcessing, err)
+	}
+
+	return nil
+}
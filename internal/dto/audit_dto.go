@@ -0,0 +1,77 @@
+package dto
+
+// Chain issue kinds
+const (
+	ChainIssueModified    = "modified"     // content no longer matches its hash
+	ChainIssueTampered    = "tampered"     // the link's own hash was edited
+	ChainIssueBrokenLink  = "broken_link"  // prevHash doesn't match the previous log
+	ChainIssueMissing     = "missing"      // sequence numbers are absent
+	ChainIssueTruncated   = "truncated"    // logs after the last one were deleted
+	ChainIssueStartsLater = "starts_later" // warning: the oldest logs are gone without a tombstone
+
+	// Notices: changes recorded by a tombstone whose link still verifies
+	ChainIssueAnonymized = "anonymized" // rewritten by user erasure
+	ChainIssueErased     = "erased"     // deleted by user erasure
+	ChainIssuePurged     = "purged"     // deleted by retention purge
+)
+
+// ChainIssue is one problem found in a hash chain partition
+type ChainIssue struct {
+	Kind    string `json:"kind"`
+	Seq     int64  `json:"seq"`
+	EventID string `json:"eventId,omitempty"`
+	Detail  string `json:"detail"`
+}
+
+// ChainReport is the result of verifying one hash chain partition
+type ChainReport struct {
+	Partition string       `json:"partition"`
+	Records   int64        `json:"records"`
+	FirstSeq  int64        `json:"firstSeq"`
+	LastSeq   int64        `json:"lastSeq"`
+	HeadSeq   int64        `json:"headSeq"`
+	Issues    []ChainIssue `json:"issues,omitempty"`
+}
+
+// Intact reports whether the partition has no issue other than warnings
+// and notices
+func (r *ChainReport) Intact() bool {
+	for _, issue := range r.Issues {
+		switch issue.Kind {
+		case ChainIssueStartsLater, ChainIssueAnonymized, ChainIssueErased, ChainIssuePurged:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Log status of an inclusion proof, comparing the live log with the leaf
+const (
+	LogStatusUnchanged = "unchanged"
+	LogStatusModified  = "modified"
+	LogStatusDeleted   = "deleted"
+
+	// Changed with a tombstone keeping the signed content hash
+	LogStatusAnonymized = "anonymized"
+	LogStatusErased     = "erased"
+	LogStatusPurged     = "purged"
+)
+
+// InclusionProof shows that a log is one of the leaves of a signed
+// checkpoint. Hashes are hex; the path runs from the leaf up to the root.
+// LogStatus tells whether the live log still has the signed content hash.
+type InclusionProof struct {
+	EventID      string   `json:"eventId"`
+	CheckpointID string   `json:"checkpointId"`
+	LeafIndex    int      `json:"leafIndex"`
+	TreeSize     int      `json:"treeSize"`
+	ContentHash  string   `json:"contentHash"`
+	LeafHash     string   `json:"leafHash"`
+	Path         []string `json:"path"`
+	Root         string   `json:"root"`
+	KeyID        string   `json:"keyId"`
+	PublicKey    string   `json:"publicKey"`
+	Signature    string   `json:"signature"`
+	LogStatus    string   `json:"logStatus"`
+}
@@ -6,5 +6,17 @@ type GenericEvent struct {
 	Topic         string                 `json:"topic"`
 	SourceService string                 `json:"sourceService"`
 	Timestamp     string                 `json:"timestamp"`
+	SchemaVersion int                    `json:"schemaVersion,omitempty"` // payload shape version of the topic; absent means 1
+	Subject       string                 `json:"subject,omitempty"`
 	Payload       map[string]interface{} `json:"payload"`
+	Extensions    map[string]interface{} `json:"extensions,omitempty"` // CloudEvents extension attributes
+	Metadata      map[string]interface{} `json:"-"`                    // delivery metadata, never read from the body
+}
+
+// SetMetadata sets one delivery metadata entry
+func (e *GenericEvent) SetMetadata(key string, value interface{}) {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]interface{})
+	}
+	e.Metadata[key] = value
 }
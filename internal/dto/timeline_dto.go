@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// TimelineEntry is one activity in a user's timeline. Roles tells whether
+// the user was the actor, the subject, or both.
+type TimelineEntry struct {
+	EventID       string                 `json:"eventId"`
+	Topic         string                 `json:"topic"`
+	SourceService string                 `json:"sourceService"`
+	Timestamp     time.Time              `json:"timestamp"`
+	SchemaVersion int                    `json:"schemaVersion"` // version of the payload shape returned
+	Roles         []string               `json:"roles"`
+	Payload       map[string]interface{} `json:"payload"`
+}
+
+// TimelineResponse is one page of a user's timeline, newest first. Pass
+// NextCursor back to get the next page; it is empty on the last page.
+type TimelineResponse struct {
+	UserID     string          `json:"userId"`
+	Entries    []TimelineEntry `json:"entries"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
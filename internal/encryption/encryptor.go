@@ -0,0 +1,247 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"event_service/internal/common"
+	"event_service/internal/models"
+	"event_service/pkg/setting"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Encrypted values are stored as strings with this prefix
+const ciphertextPrefix = "enc:v1:"
+
+type rule struct {
+	topics []string
+	paths  []string
+}
+
+// Encryptor encrypts the configured payload paths of activity logs with the
+// active data key and decrypts them again for authorized reads
+type Encryptor struct {
+	keyring *Keyring
+	rules   []rule
+}
+
+var (
+	currentMu sync.RWMutex
+	current   *Encryptor
+)
+
+// Current returns the encryptor set up at startup, or nil when encryption
+// is disabled
+func Current() *Encryptor {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+
+	return current
+}
+
+// SetCurrent makes e the encryptor used by the log and query services
+func SetCurrent(e *Encryptor) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+
+	current = e
+}
+
+func New(cfg setting.Encryption, keyring *Keyring) *Encryptor {
+	e := &Encryptor{keyring: keyring}
+	for _, r := range cfg.Rules {
+		e.rules = append(e.rules, rule{topics: r.Topics, paths: r.Paths})
+	}
+	return e
+}
+
+func (e *Encryptor) Keyring() *Keyring {
+	return e.keyring
+}
+
+// Encrypt replaces the values at the paths configured for the log's topic
+// with their ciphertext and records the data key and paths on the log
+func (e *Encryptor) Encrypt(ctx context.Context, log *models.ActivityLog) error {
+	paths := e.pathsFor(log.Topic)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	keyID, key, err := e.keyring.Active(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrEncryption, err)
+	}
+
+	var encrypted []string
+	for _, p := range paths {
+		segments := strings.Split(p, ".")
+		value, exists := lookup(log.Payload, segments)
+		if !exists {
+			continue
+		}
+
+		plaintext, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", common.ErrEncryption, p, err)
+		}
+
+		sealed, err := seal(key, plaintext, fieldAdditionalData(log.EventID, p))
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", common.ErrEncryption, p, err)
+		}
+
+		assign(log.Payload, segments, ciphertextPrefix+base64.StdEncoding.EncodeToString(sealed))
+		encrypted = append(encrypted, p)
+	}
+
+	if len(encrypted) > 0 {
+		log.Encryption = &models.EncryptionInfo{KeyID: keyID, Paths: encrypted}
+	}
+	return nil
+}
+
+// Decrypt restores the plaintext of an encrypted log in place
+func (e *Encryptor) Decrypt(ctx context.Context, log *models.ActivityLog) error {
+	if log.Encryption == nil {
+		return nil
+	}
+
+	key, err := e.keyring.Key(ctx, log.Encryption.KeyID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrDecryption, err)
+	}
+
+	for _, p := range log.Encryption.Paths {
+		segments := strings.Split(p, ".")
+		value, exists := lookup(log.Payload, segments)
+		encoded, isString := value.(string)
+		if !exists || !isString || !strings.HasPrefix(encoded, ciphertextPrefix) {
+			continue
+		}
+
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encoded, ciphertextPrefix))
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", common.ErrDecryption, p, err)
+		}
+
+		plaintext, err := open(key, sealed, fieldAdditionalData(log.EventID, p))
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", common.ErrDecryption, p, err)
+		}
+
+		var decrypted interface{}
+		if err := json.Unmarshal(plaintext, &decrypted); err != nil {
+			return fmt.Errorf("%w: %s: %w", common.ErrDecryption, p, err)
+		}
+		assign(log.Payload, segments, decrypted)
+	}
+
+	log.Encryption = nil
+	return nil
+}
+
+func (e *Encryptor) pathsFor(topic string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+
+	for _, r := range e.rules {
+		if !matchesTopic(r.topics, topic) {
+			continue
+		}
+		for _, p := range r.paths {
+			if !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+
+	return paths
+}
+
+func matchesTopic(patterns []string, topic string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, topic); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// A ciphertext only decrypts under the event and path it was made for, so
+// it can't be copied into another document or field
+func fieldAdditionalData(eventID, path string) []byte {
+	return []byte(eventID + "\x00" + path)
+}
+
+// lookup returns the value at segments. Payloads read back from MongoDB may
+// hold nested documents as primitive.M or primitive.D.
+func lookup(value interface{}, segments []string) (interface{}, bool) {
+	current := value
+	for _, segment := range segments {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, exists := v[segment]
+			if !exists {
+				return nil, false
+			}
+			current = next
+		case primitive.M:
+			next, exists := v[segment]
+			if !exists {
+				return nil, false
+			}
+			current = next
+		case primitive.D:
+			found := false
+			for _, element := range v {
+				if element.Key == segment {
+					current, found = element.Value, true
+					break
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// assign sets the value at segments, which must already exist
+func assign(value interface{}, segments []string, newValue interface{}) bool {
+	parent, exists := lookup(value, segments[:len(segments)-1])
+	if !exists {
+		return false
+	}
+
+	last := segments[len(segments)-1]
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		v[last] = newValue
+	case primitive.M:
+		v[last] = newValue
+	case primitive.D:
+		for i := range v {
+			if v[i].Key == last {
+				v[i].Value = newValue
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,281 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"event_service/internal/models"
+	"event_service/internal/repo"
+)
+
+const (
+	keySize = 32 // AES-256
+
+	// How often the newest data key is looked up again, so a key rotated on
+	// another instance is picked up
+	activeKeyRefresh = 5 * time.Minute
+)
+
+// Keyring hands out unwrapped data keys. Data keys are stored in MongoDB
+// wrapped with a master key and cached unwrapped in memory.
+type Keyring struct {
+	master     *MasterKeys
+	repository repo.DataKeyRepository
+
+	mu              sync.Mutex
+	keys            map[string][]byte
+	activeID        string
+	activeCheckedAt time.Time
+}
+
+func NewKeyring(master *MasterKeys, repository repo.DataKeyRepository) *Keyring {
+	return &Keyring{
+		master:     master,
+		repository: repository,
+		keys:       make(map[string][]byte),
+	}
+}
+
+// Active returns the data key new documents are encrypted with, creating
+// the first one if none exists. While MongoDB is unreachable the cached key
+// keeps being used.
+func (k *Keyring) Active(ctx context.Context) (string, []byte, error) {
+	k.mu.Lock()
+	activeID, checkedAt := k.activeID, k.activeCheckedAt
+	k.mu.Unlock()
+
+	if activeID != "" && time.Since(checkedAt) < activeKeyRefresh {
+		key, err := k.Key(ctx, activeID)
+		return activeID, key, err
+	}
+
+	latest, err := k.repository.Latest(ctx)
+	if err != nil {
+		if activeID != "" {
+			key, keyErr := k.Key(ctx, activeID)
+			return activeID, key, keyErr
+		}
+		return "", nil, err
+	}
+	if latest == nil {
+		if latest, err = k.Rotate(ctx); err != nil {
+			return "", nil, err
+		}
+	}
+
+	key, err := k.unwrap(latest)
+	if err != nil {
+		return "", nil, err
+	}
+
+	k.mu.Lock()
+	k.keys[latest.ID] = key
+	k.activeID = latest.ID
+	k.activeCheckedAt = time.Now()
+	k.mu.Unlock()
+
+	return latest.ID, key, nil
+}
+
+// Preload unwraps every stored data key into the cache and makes the newest
+// one active, creating it if none exists. After Preload, encryption and
+// decryption keep working from the cache while MongoDB is unreachable.
+func (k *Keyring) Preload(ctx context.Context) (string, error) {
+	dataKeys, err := k.repository.List(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range dataKeys {
+		key, err := k.unwrap(&dataKeys[i])
+		if err != nil {
+			return "", err
+		}
+
+		k.mu.Lock()
+		k.keys[dataKeys[i].ID] = key
+		k.mu.Unlock()
+	}
+
+	activeID, _, err := k.Active(ctx)
+	return activeID, err
+}
+
+// Key returns the data key id
+func (k *Keyring) Key(ctx context.Context, id string) ([]byte, error) {
+	k.mu.Lock()
+	key, cached := k.keys[id]
+	k.mu.Unlock()
+	if cached {
+		return key, nil
+	}
+
+	dataKey, err := k.repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dataKey == nil {
+		return nil, fmt.Errorf("data key %s not found", id)
+	}
+
+	key, err = k.unwrap(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.keys[id] = key
+	k.mu.Unlock()
+	return key, nil
+}
+
+// Rotate creates a new data key, wrapped with the active master key, that
+// encrypts documents from now on. Documents keep naming the key they were
+// encrypted with, so older keys remain usable for reads.
+func (k *Keyring) Rotate(ctx context.Context) (*models.DataKey, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	id := "dk-" + now.Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+
+	wrapped, err := k.wrap(id, k.master.ActiveID(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := &models.DataKey{
+		ID:          id,
+		MasterKeyID: k.master.ActiveID(),
+		WrappedKey:  wrapped,
+		CreatedAt:   now,
+	}
+	if err := k.repository.Create(ctx, dataKey); err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.keys[id] = key
+	k.activeID = id
+	k.activeCheckedAt = time.Now()
+	k.mu.Unlock()
+
+	fmt.Printf("Created data key %s (master key %s)\n", id, dataKey.MasterKeyID)
+	return dataKey, nil
+}
+
+// Rewrap wraps every data key still wrapped with a retired master key with
+// the active one and returns how many were rewrapped. Documents are not
+// touched.
+func (k *Keyring) Rewrap(ctx context.Context) (int, error) {
+	keys, err := k.repository.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for i := range keys {
+		dataKey := &keys[i]
+		if dataKey.MasterKeyID == k.master.ActiveID() {
+			continue
+		}
+
+		key, err := k.unwrap(dataKey)
+		if err != nil {
+			return rewrapped, err
+		}
+		wrapped, err := k.wrap(dataKey.ID, k.master.ActiveID(), key)
+		if err != nil {
+			return rewrapped, err
+		}
+		if err := k.repository.Rewrap(ctx, dataKey.ID, k.master.ActiveID(), wrapped); err != nil {
+			return rewrapped, err
+		}
+		rewrapped++
+	}
+
+	return rewrapped, nil
+}
+
+// MasterKeyID returns the id of the active master key
+func (k *Keyring) MasterKeyID() string {
+	return k.master.ActiveID()
+}
+
+// Keys lists the data keys, oldest first
+func (k *Keyring) Keys(ctx context.Context) ([]models.DataKey, error) {
+	return k.repository.List(ctx)
+}
+
+// The data key id is authenticated with the key so a wrapped key can't be
+// swapped onto another id
+func (k *Keyring) wrap(id, masterKeyID string, key []byte) ([]byte, error) {
+	master, err := k.master.key(masterKeyID)
+	if err != nil {
+		return nil, err
+	}
+	return seal(master, key, []byte(id))
+}
+
+func (k *Keyring) unwrap(dataKey *models.DataKey) ([]byte, error) {
+	master, err := k.master.key(dataKey.MasterKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("data key %s: %w", dataKey.ID, err)
+	}
+
+	key, err := open(master, dataKey.WrappedKey, []byte(dataKey.ID))
+	if err != nil {
+		return nil, fmt.Errorf("data key %s: failed to unwrap: %w", dataKey.ID, err)
+	}
+	return key, nil
+}
+
+// seal encrypts plaintext with AES-256-GCM, returning nonce || ciphertext
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,72 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// keyFile is the JSON layout of the master key file:
+//
+//	{"active": "master-2026-10", "keys": {"master-2026-10": "<base64 32 bytes>"}}
+//
+// Retired master keys stay in the file until every data key has been
+// rewrapped with the active one.
+type keyFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"`
+}
+
+// MasterKeys are the AES-256 keys that wrap the data keys
+type MasterKeys struct {
+	active string
+	keys   map[string][]byte
+}
+
+// LoadMasterKeys reads the master key file at path
+func LoadMasterKeys(path string) (*MasterKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key file: %v", err)
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid master key file: %v", err)
+	}
+
+	m := &MasterKeys{
+		active: file.Active,
+		keys:   make(map[string][]byte, len(file.Keys)),
+	}
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %s is not valid base64: %v", id, err)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("master key %s must be %d bytes, got %d", id, keySize, len(key))
+		}
+		m.keys[id] = key
+	}
+
+	if _, exists := m.keys[m.active]; !exists {
+		return nil, fmt.Errorf("active master key %q is not in the key file", m.active)
+	}
+
+	return m, nil
+}
+
+// ActiveID returns the id of the master key new data keys are wrapped with
+func (m *MasterKeys) ActiveID() string {
+	return m.active
+}
+
+func (m *MasterKeys) key(id string) ([]byte, error) {
+	key, exists := m.keys[id]
+	if !exists {
+		return nil, fmt.Errorf("master key %q is not in the key file", id)
+	}
+	return key, nil
+}
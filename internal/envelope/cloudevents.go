@@ -0,0 +1,215 @@
+package envelope
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"event_service/internal/dto"
+)
+
+const (
+	// CloudEventsContentType marks a structured mode CloudEvent
+	CloudEventsContentType = "application/cloudevents+json"
+	CloudEventsSpecVersion = "1.0"
+)
+
+// Prefixes of CloudEvents attributes carried as application properties in
+// binary mode; "cloudEvents_" is the JMS-compatible form
+var cloudEventsHeaderPrefixes = []string{"cloudEvents:", "cloudEvents_"}
+
+// Attributes a CloudEvent maps onto the envelope or that describe its data;
+// every other attribute is an extension
+var cloudEventsContextAttributes = map[string]bool{
+	"specversion":     true,
+	"id":              true,
+	"type":            true,
+	"source":          true,
+	"time":            true,
+	"subject":         true,
+	"datacontenttype": true,
+	"dataschema":      true,
+	"data":            true,
+	"data_base64":     true,
+}
+
+// isStructuredCloudEvent reports whether the content type is the
+// structured mode CloudEvents JSON format
+func isStructuredCloudEvent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == CloudEventsContentType
+}
+
+// binaryCloudEventAttributes returns the CloudEvents attributes carried in
+// the message headers, or nil when the message is not a binary mode
+// CloudEvent
+func binaryCloudEventAttributes(headers map[string]interface{}) map[string]interface{} {
+	var attributes map[string]interface{}
+	for key, value := range headers {
+		for _, prefix := range cloudEventsHeaderPrefixes {
+			if name, found := strings.CutPrefix(key, prefix); found {
+				if attributes == nil {
+					attributes = make(map[string]interface{})
+				}
+				attributes[name] = value
+			}
+		}
+	}
+
+	if _, exists := attributes["specversion"]; !exists {
+		return nil
+	}
+	return attributes
+}
+
+// decodeStructuredCloudEvent decodes a CloudEvent whose attributes and data
+// are all in the JSON body
+func decodeStructuredCloudEvent(body []byte) (*dto.GenericEvent, error) {
+	var attributes map[string]interface{}
+	if err := json.Unmarshal(body, &attributes); err != nil {
+		return nil, err
+	}
+
+	event, err := cloudEventEnvelope(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := attributeString(attributes["datacontenttype"])
+	if encoded, exists := attributes["data_base64"]; exists {
+		data, err := base64.StdEncoding.DecodeString(attributeString(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("cloudevent data_base64: %v", err)
+		}
+		event.Payload, err = cloudEventPayload(contentType, data)
+		if err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+
+	event.Payload = payloadObject(attributes["data"])
+	return event, nil
+}
+
+// decodeBinaryCloudEvent decodes a CloudEvent whose attributes are in the
+// headers and whose body is the data, typed by the message content type
+func decodeBinaryCloudEvent(attributes map[string]interface{}, contentType string, body []byte) (*dto.GenericEvent, error) {
+	event, err := cloudEventEnvelope(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	event.Payload, err = cloudEventPayload(contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// cloudEventEnvelope maps the context attributes of a CloudEvent onto the
+// envelope: id, type as the topic, source as the source service, time,
+// subject, and the remaining attributes as extensions
+func cloudEventEnvelope(attributes map[string]interface{}) (*dto.GenericEvent, error) {
+	if version := attributeString(attributes["specversion"]); version != CloudEventsSpecVersion {
+		return nil, fmt.Errorf("unsupported cloudevents specversion %q", version)
+	}
+
+	event := &dto.GenericEvent{
+		EventID:       attributeString(attributes["id"]),
+		Topic:         attributeString(attributes["type"]),
+		SourceService: attributeString(attributes["source"]),
+		Timestamp:     attributeString(attributes["time"]),
+		Subject:       attributeString(attributes["subject"]),
+	}
+
+	for name, value := range attributes {
+		if cloudEventsContextAttributes[name] {
+			continue
+		}
+		if event.Extensions == nil {
+			event.Extensions = make(map[string]interface{})
+		}
+		event.Extensions[name] = extensionValue(value)
+	}
+
+	return event, nil
+}
+
+// cloudEventPayload decodes CloudEvent data: JSON data becomes the payload,
+// anything else is kept base64-encoded under "data_base64"
+func cloudEventPayload(contentType string, data []byte) (map[string]interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if !isJSONContentType(contentType) {
+		return map[string]interface{}{"data_base64": base64.StdEncoding.EncodeToString(data)}, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("cloudevent data: %v", err)
+	}
+	return payloadObject(value), nil
+}
+
+// payloadObject uses JSON object data as the payload and wraps any other
+// value under "data"
+func payloadObject(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return v
+	default:
+		return map[string]interface{}{"data": v}
+	}
+}
+
+// isJSONContentType reports whether data of this content type is JSON; the
+// CloudEvents default for absent datacontenttype is JSON
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// attributeString reads an attribute that may arrive as a string, bytes or
+// an AMQP timestamp
+func attributeString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// extensionValue keeps extension values storable: AMQP byte arrays become
+// strings, timestamps RFC 3339 strings
+func extensionValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
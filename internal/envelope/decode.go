@@ -0,0 +1,87 @@
+package envelope
+
+import (
+	"mime"
+	"sort"
+	"strings"
+	"sync"
+
+	"event_service/internal/dto"
+)
+
+//go:generate protoc -I ../../proto --go_out=envelopepb --go_opt=paths=source_relative envelope.proto
+
+// Decoder decodes a message body of one content type into an event
+type Decoder func(body []byte) (*dto.GenericEvent, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{
+		"application/json":        decodeJSON,
+		"text/json":               decodeJSON,
+		CloudEventsContentType:    decodeStructuredCloudEvent,
+		"application/x-protobuf":  decodeProtobuf,
+		"application/protobuf":    decodeProtobuf,
+		"application/x-msgpack":   decodeMsgpack,
+		"application/msgpack":     decodeMsgpack,
+		"application/vnd.msgpack": decodeMsgpack,
+	}
+)
+
+// RegisterDecoder makes decoder handle bodies of contentType (a media type
+// without parameters), replacing any decoder previously registered for it
+func RegisterDecoder(contentType string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	decoders[strings.ToLower(contentType)] = decoder
+}
+
+// ContentTypes returns the content types with a decoder in a stable order
+func ContentTypes() []string {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	contentTypes := make([]string, 0, len(decoders))
+	for contentType := range decoders {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	return contentTypes
+}
+
+// Decode turns a message into a generic event. The body is first
+// decompressed according to its content encoding. Binary mode CloudEvents
+// (cloudEvents:* headers) carry their attributes in the headers; any other
+// body is decoded by the decoder of its content type. Messages without a
+// content type, or with one that has no decoder, are read as JSON as they
+// always were.
+func Decode(message *Message) (*dto.GenericEvent, error) {
+	body, err := decompress(message.ContentEncoding, message.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if attributes := binaryCloudEventAttributes(message.Headers); attributes != nil {
+		return decodeBinaryCloudEvent(attributes, message.ContentType, body)
+	}
+
+	return decoderFor(message.ContentType)(body)
+}
+
+func decoderFor(contentType string) Decoder {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return decodeJSON
+	}
+
+	decodersMu.RLock()
+	decoder, exists := decoders[mediaType]
+	decodersMu.RUnlock()
+
+	if !exists {
+		return decodeJSON
+	}
+	return decoder
+}
@@ -0,0 +1,63 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"event_service/internal/dto"
+	"event_service/internal/envelope/envelopepb"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// decodeJSON decodes the service's own JSON envelope
+func decodeJSON(body []byte) (*dto.GenericEvent, error) {
+	var event dto.GenericEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// decodeProtobuf decodes the envelope defined in proto/envelope.proto.
+// Payload numbers arrive as float64, as in JSON.
+func decodeProtobuf(body []byte) (*dto.GenericEvent, error) {
+	var envelope envelopepb.Envelope
+	if err := proto.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid protobuf envelope: %v", err)
+	}
+
+	event := &dto.GenericEvent{
+		EventID:       envelope.GetEventId(),
+		Topic:         envelope.GetTopic(),
+		SourceService: envelope.GetSourceService(),
+		SchemaVersion: int(envelope.GetSchemaVersion()),
+		Subject:       envelope.GetSubject(),
+	}
+	if timestamp := envelope.GetTimestamp(); timestamp != nil {
+		event.Timestamp = timestamp.AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	if payload := envelope.GetPayload(); payload != nil {
+		event.Payload = payload.AsMap()
+	}
+	if extensions := envelope.GetExtensions(); extensions != nil && len(extensions.GetFields()) > 0 {
+		event.Extensions = extensions.AsMap()
+	}
+
+	return event, nil
+}
+
+// decodeMsgpack decodes the JSON envelope fields from a MessagePack map
+func decodeMsgpack(body []byte) (*dto.GenericEvent, error) {
+	decoder := msgpack.NewDecoder(bytes.NewReader(body))
+	decoder.SetCustomStructTag("json")
+
+	var event dto.GenericEvent
+	if err := decoder.Decode(&event); err != nil {
+		return nil, fmt.Errorf("invalid msgpack envelope: %v", err)
+	}
+	return &event, nil
+}
@@ -0,0 +1,52 @@
+package envelope
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxDecompressedSize bounds what a compressed body may expand to
+const maxDecompressedSize = 64 << 20
+
+// decompress undoes the content encoding of a body: gzip, zstd, or none
+func decompress(contentEncoding string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return body, nil
+
+	case "gzip", "x-gzip":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %v", err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+
+	case "zstd":
+		zstdReader, err := zstd.NewReader(bytes.NewReader(body), zstd.WithDecoderMaxMemory(maxDecompressedSize))
+		if err != nil {
+			return nil, fmt.Errorf("invalid zstd body: %v", err)
+		}
+		defer zstdReader.Close()
+		reader = zstdReader
+
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", contentEncoding)
+	}
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s body: %v", contentEncoding, err)
+	}
+	if len(decompressed) > maxDecompressedSize {
+		return nil, fmt.Errorf("%s body expands past %d bytes", contentEncoding, maxDecompressedSize)
+	}
+
+	return decompressed, nil
+}
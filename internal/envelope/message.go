@@ -0,0 +1,48 @@
+package envelope
+
+import (
+	"time"
+
+	"event_service/internal/dto"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Message is a delivery as handlers see it: the body and the properties
+// describing it
+type Message struct {
+	Body            []byte
+	ContentType     string
+	ContentEncoding string
+	Headers         map[string]interface{}
+	RoutingKey      string
+	MessageID       string
+	CorrelationID   string
+	Timestamp       time.Time
+	AppID           string
+	Type            string
+	UserID          string
+
+	// Event and DecodeErr are the outcome of decoding the message once
+	// before it reached its handler, e.g. to find its ordering key; the
+	// handler reuses them instead of decoding again
+	Event     *dto.GenericEvent
+	DecodeErr error
+}
+
+// FromDelivery copies what handlers need from an AMQP delivery
+func FromDelivery(delivery amqp091.Delivery) *Message {
+	return &Message{
+		Body:            delivery.Body,
+		ContentType:     delivery.ContentType,
+		ContentEncoding: delivery.ContentEncoding,
+		Headers:         delivery.Headers,
+		RoutingKey:      delivery.RoutingKey,
+		MessageID:       delivery.MessageId,
+		CorrelationID:   delivery.CorrelationId,
+		Timestamp:       delivery.Timestamp,
+		AppID:           delivery.AppId,
+		Type:            delivery.Type,
+		UserID:          delivery.UserId,
+	}
+}
@@ -0,0 +1,107 @@
+package envelope
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"event_service/internal/dto"
+	"event_service/pkg/setting"
+)
+
+const headerSourcePrefix = "header:"
+
+var propertySources = map[string]bool{
+	"message_id":     true,
+	"correlation_id": true,
+	"timestamp":      true,
+	"app_id":         true,
+	"type":           true,
+	"user_id":        true,
+	"routing_key":    true,
+}
+
+// IsPropertySource reports whether source names an AMQP property, the
+// routing key or a header:<name>
+func IsPropertySource(source string) bool {
+	if name, found := strings.CutPrefix(source, headerSourcePrefix); found {
+		return name != ""
+	}
+	return propertySources[source]
+}
+
+// Property returns the value of a property source, empty when unset
+func (m *Message) Property(source string) string {
+	if name, found := strings.CutPrefix(source, headerSourcePrefix); found {
+		return attributeString(m.Headers[name])
+	}
+
+	switch source {
+	case "message_id":
+		return m.MessageID
+	case "correlation_id":
+		return m.CorrelationID
+	case "timestamp":
+		if m.Timestamp.IsZero() {
+			return ""
+		}
+		return m.Timestamp.UTC().Format(time.RFC3339)
+	case "app_id":
+		return m.AppID
+	case "type":
+		return m.Type
+	case "user_id":
+		return m.UserID
+	case "routing_key":
+		return m.RoutingKey
+	}
+	return ""
+}
+
+// FillFromProperties fills the envelope fields event lacks from the
+// message properties configured in fallback, and records where they came
+// from and the configured headers in the event metadata
+func FillFromProperties(event *dto.GenericEvent, message *Message, fallback setting.PropertyFallback) {
+	filledFrom := make(map[string]interface{})
+	fill := func(field string, value *string, sources []string) {
+		if *value != "" {
+			return
+		}
+		for _, source := range sources {
+			if property := message.Property(source); property != "" {
+				*value = property
+				filledFrom[field] = source
+				return
+			}
+		}
+	}
+
+	fill("eventId", &event.EventID, fallback.EventID)
+	fill("timestamp", &event.Timestamp, fallback.Timestamp)
+	fill("sourceService", &event.SourceService, fallback.SourceService)
+	fill("topic", &event.Topic, fallback.Topic)
+
+	if fallback.PayloadFromBody && event.Payload == nil && isJSONContentType(message.ContentType) {
+		if body, err := decompress(message.ContentEncoding, message.Body); err == nil {
+			var payload map[string]interface{}
+			if json.Unmarshal(body, &payload) == nil && payload != nil {
+				event.Payload = payload
+				filledFrom["payload"] = "body"
+			}
+		}
+	}
+
+	headers := make(map[string]interface{})
+	for _, name := range fallback.Headers {
+		if value, exists := message.Headers[name]; exists {
+			headers[name] = extensionValue(value)
+		}
+	}
+
+	if len(filledFrom) > 0 {
+		event.SetMetadata("filledFrom", filledFrom)
+	}
+	if len(headers) > 0 {
+		event.SetMetadata("headers", headers)
+	}
+}
@@ -0,0 +1,118 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// Component states reported on the readiness endpoint
+const (
+	StatePending    = "pending"
+	StateConnecting = "connecting"
+	StateReady      = "ready"
+	StateFailed     = "failed"
+	StateStopping   = "stopping"
+)
+
+// ComponentStatus is the readiness state of a single dependency or subsystem
+type ComponentStatus struct {
+	Name      string            `json:"name"`
+	State     string            `json:"state"`
+	Attempts  int               `json:"attempts,omitempty"`
+	LastError string            `json:"lastError,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+var (
+	mu         sync.RWMutex
+	components = make(map[string]*ComponentStatus)
+	order      []string
+)
+
+// Register adds a component in the pending state. The service is not ready
+// until every registered component reports StateReady.
+func Register(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := components[name]; exists {
+		return
+	}
+
+	components[name] = &ComponentStatus{
+		Name:      name,
+		State:     StatePending,
+		UpdatedAt: time.Now(),
+	}
+	order = append(order, name)
+}
+
+// SetState updates the state of a component, registering it if needed
+func SetState(name, state string, attempts int, lastErr error) {
+	Register(name)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	status := components[name]
+	status.State = state
+	status.Attempts = attempts
+	status.LastError = ""
+	if lastErr != nil {
+		status.LastError = lastErr.Error()
+	}
+	status.UpdatedAt = time.Now()
+}
+
+// SetDetails replaces the informational details of a component without
+// changing its state. A component first seen here is registered as ready,
+// since details alone say nothing about readiness.
+func SetDetails(name string, details map[string]string) {
+	copied := make(map[string]string, len(details))
+	for key, value := range details {
+		copied[key] = value
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	status, exists := components[name]
+	if !exists {
+		status = &ComponentStatus{
+			Name:      name,
+			State:     StateReady,
+			UpdatedAt: time.Now(),
+		}
+		components[name] = status
+		order = append(order, name)
+	}
+	status.Details = copied
+}
+
+// Ready reports whether every registered component is ready
+func Ready() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, status := range components {
+		if status.State != StateReady {
+			return false
+		}
+	}
+
+	return len(components) > 0
+}
+
+// Snapshot returns a copy of all component states in registration order
+func Snapshot() []ComponentStatus {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make([]ComponentStatus, 0, len(order))
+	for _, name := range order {
+		result = append(result, *components[name])
+	}
+
+	return result
+}
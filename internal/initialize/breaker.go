@@ -0,0 +1,28 @@
+package initialize
+
+import (
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/breaker"
+)
+
+// InitCircuitBreaker creates the breaker guarding MongoDB writes. Consumers
+// subscribe to it to pause consumption while it is open.
+func InitCircuitBreaker() {
+	cfg := global.Config.MongoDB.CircuitBreaker
+	if !cfg.Enabled {
+		return
+	}
+
+	global.MongoBreaker = breaker.New("mongodb", breaker.Options{
+		WindowSize:           cfg.WindowSize,
+		MinRequests:          cfg.MinRequests,
+		FailureRateThreshold: cfg.FailureRateThreshold,
+		OpenDuration:         time.Duration(cfg.OpenSeconds) * time.Second,
+		HalfOpenMaxProbes:    cfg.HalfOpenMaxProbes,
+	})
+
+	fmt.Println("MongoDB circuit breaker enabled")
+}
@@ -2,9 +2,7 @@ package initialize
 
 import (
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
+	"sort"
 
 	"event_service/global"
 	"event_service/internal/consumers"
@@ -12,57 +10,43 @@ import (
 
 var ConsumerManager *consumers.ConsumerManager
 
-func InitConsumers() {
+// InitConsumers creates one consumer per entry in rabbitmq.consumers, each
+// feeding the handler it names, and starts them
+func InitConsumers() error {
 	fmt.Println("Initializing consumers...")
 
 	// Create consumer manager
 	ConsumerManager = consumers.NewConsumerManager()
 
-	// Register consumers
-	activityLogConsumer := consumers.NewActivityLogConsumer()
-	ConsumerManager.RegisterConsumer(activityLogConsumer)
+	for _, name := range consumerNames() {
+		cfg := global.Config.RabbitMQ.Consumers[name]
 
-	// Start all consumers
-	err := ConsumerManager.StartAll()
-	if err != nil {
-		panic(fmt.Errorf("failed to start consumers: %v", err))
-	}
-
-	// Setup graceful shutdown
-	setupGracefulShutdown()
-
-	fmt.Printf("Consumers initialized successfully: %v\n", ConsumerManager.GetConsumerNames())
-}
-
-func setupGracefulShutdown() {
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
-
-	go func() {
-		<-c
-		fmt.Println("Received shutdown signal, stopping consumers...")
-
-		err := ConsumerManager.StopAll()
+		handler, err := consumers.NewHandler(cfg.Handler)
 		if err != nil {
-			fmt.Printf("Error stopping consumers: %v\n", err)
+			return fmt.Errorf("consumer %s: %w", name, err)
 		}
 
-		// Close RabbitMQ connections
-		if global.ActivityLogRabbitCh != nil {
-			global.ActivityLogRabbitCh.Close()
-		}
-		if global.RabbitMQ != nil {
-			global.RabbitMQ.Close()
-		}
+		ConsumerManager.RegisterConsumer(consumers.NewQueueConsumer(cfg, handler))
+	}
 
-		fmt.Println("Graceful shutdown completed")
-		os.Exit(0)
-	}()
+	// Start all consumers. One that fails to start is reported on the
+	// readiness endpoint while the others keep consuming.
+	if err := ConsumerManager.StartAll(); err != nil {
+		fmt.Printf("Warning: failed to start consumers: %v\n", err)
+		return nil
+	}
+
+	fmt.Printf("Consumers initialized successfully: %v\n", ConsumerManager.GetConsumerNames())
+	return nil
 }
 
-func WaitForConsumers() {
-	if ConsumerManager != nil {
-		fmt.Println("Waiting for consumers...")
-		ConsumerManager.Wait()
+// consumerNames returns the configured consumer names in a stable order
+func consumerNames() []string {
+	names := make([]string, 0, len(global.Config.RabbitMQ.Consumers))
+	for name := range global.Config.RabbitMQ.Consumers {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+
+	return names
 }
@@ -0,0 +1,53 @@
+package initialize
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/encryption"
+	"event_service/internal/repo"
+)
+
+// NewEncryptor loads the master keys and builds the field encryptor without
+// making it current
+func NewEncryptor() (*encryption.Encryptor, error) {
+	cfg := global.Config.Encryption
+
+	master, err := encryption.LoadMasterKeys(cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring := encryption.NewKeyring(master, repo.NewDataKeyRepository())
+	return encryption.New(cfg, keyring), nil
+}
+
+// InitEncryption sets up field-level encryption when enabled. The data keys
+// are loaded (the active one created if needed) up front, so a bad key file
+// stops startup rather than failing every event, and a MongoDB outage later
+// does not leave the encryptor without a key.
+func InitEncryption() error {
+	if !global.Config.Encryption.Enabled {
+		fmt.Println("Field encryption disabled")
+		return nil
+	}
+
+	encryptor, err := NewEncryptor()
+	if err != nil {
+		return fmt.Errorf("failed to set up field encryption: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	keyID, err := encryptor.Keyring().Preload(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the data keys: %w", err)
+	}
+
+	encryption.SetCurrent(encryptor)
+	fmt.Printf("Field encryption enabled with data key %s\n", keyID)
+	return nil
+}
@@ -0,0 +1,49 @@
+package initialize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/api"
+)
+
+var HTTPServer *http.Server
+
+// InitHTTPServer starts the operational HTTP endpoints in the background. It
+// is started before the dependencies so readiness can be observed while the
+// service is still connecting.
+func InitHTTPServer() {
+	cfg := global.Config.Server
+	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+
+	HTTPServer = &http.Server{
+		Addr:              addr,
+		Handler:           api.NewRouter(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		fmt.Printf("HTTP server listening on %s\n", addr)
+		err := HTTPServer.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("HTTP server error: %v\n", err)
+		}
+	}()
+}
+
+func stopHTTPServer() {
+	if HTTPServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := HTTPServer.Shutdown(ctx); err != nil {
+		fmt.Printf("Error shutting down HTTP server: %v\n", err)
+	}
+}
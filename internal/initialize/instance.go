@@ -0,0 +1,24 @@
+package initialize
+
+import (
+	"fmt"
+	"os"
+
+	"event_service/global"
+)
+
+// InitInstanceID sets the identity of this replica, used to tell replicas
+// apart on the broker and in MongoDB
+func InitInstanceID() {
+	id := global.Config.Server.InstanceID
+	if id == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "event-service"
+		}
+		id = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	global.InstanceID = id
+	fmt.Printf("Instance ID: %s\n", id)
+}
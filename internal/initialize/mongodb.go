@@ -12,9 +12,14 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func InitMongoDB() {
+// InitMongoDB connects to MongoDB, retrying until ctx is done
+func InitMongoDB(ctx context.Context) error {
 	fmt.Println("Initializing MongoDB connection...")
 
+	return connectWithRetry(ctx, "mongodb", connectMongoDB)
+}
+
+func connectMongoDB(ctx context.Context) error {
 	cfg := global.Config.MongoDB
 
 	var uri string
@@ -27,20 +32,23 @@ func InitMongoDB() {
 
 	clientOptions := options.Client().ApplyURI(uri)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	attemptCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, clientOptions)
+	client, err := mongo.Connect(attemptCtx, clientOptions)
 	if err != nil {
-		panic(fmt.Errorf("%w: %v", common.ErrMongoConnection, err))
+		return fmt.Errorf("%w: %v", common.ErrMongoConnection, err)
 	}
 
-	err = client.Ping(ctx, nil)
+	err = client.Ping(attemptCtx, nil)
 	if err != nil {
-		panic(fmt.Errorf("%w: ping failed: %v", common.ErrMongoConnection, err))
+		client.Disconnect(context.Background())
+		return fmt.Errorf("%w: ping failed: %v", common.ErrMongoConnection, err)
 	}
 
+	global.MongoClient = client
 	global.MongoDB = client.Database(cfg.Database)
 
 	fmt.Printf("MongoDB connected successfully to database: %s\n", cfg.Database)
+	return nil
 }
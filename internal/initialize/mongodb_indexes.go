@@ -6,10 +6,8 @@ import (
 	"time"
 
 	"event_service/global"
-
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"event_service/internal/common"
+	"event_service/internal/repo"
 )
 
 func CreateMongoDBIndexes() {
@@ -21,53 +19,56 @@ func CreateMongoDBIndexes() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	indexes := []mongo.IndexModel{
-		{
-			Keys: bson.D{
-				{"topic", 1},
-				{"processedAt", -1},
-			},
-			Options: options.Index().SetName("topic_processedAt_idx"),
-		},
-		{
-			Keys: bson.D{
-				{"payload.userId", 1},
-				{"processedAt", -1},
-			},
-			Options: options.Index().SetName("userId_processedAt_idx"),
-		},
-		{
-			Keys: bson.D{
-				{"eventId", 1},
-			},
-			Options: options.Index().SetName("eventId_idx").SetUnique(true),
-		},
-		{
-			Keys: bson.D{
-				{"sourceService", 1},
-				{"processedAt", -1},
-			},
-			Options: options.Index().SetName("sourceService_processedAt_idx"),
-		},
-		{
-			Keys: bson.D{
-				{"timestamp", -1},
-			},
-			Options: options.Index().SetName("timestamp_idx"),
-		},
-		{
-			Keys: bson.D{
-				{"processedAt", -1},
-			},
-			Options: options.Index().SetName("processedAt_idx"),
-		},
-	}
-
-	names, err := collection.Indexes().CreateMany(ctx, indexes)
+	names, err := collection.Indexes().CreateMany(ctx, repo.ActivityLogIndexes())
 	if err != nil {
 		fmt.Printf("Warning: Failed to create some indexes: %v\n", err)
 		return
 	}
 
 	fmt.Printf("MongoDB indexes created successfully: %v\n", names)
+
+	rollups := global.MongoDB.Collection(common.RollupCollection)
+	names, err = rollups.Indexes().CreateMany(ctx, repo.ActivityRollupIndexes())
+	if err != nil {
+		fmt.Printf("Warning: Failed to create some rollup indexes: %v\n", err)
+		return
+	}
+
+	fmt.Printf("MongoDB rollup indexes created successfully: %v\n", names)
+
+	checkpoints := global.MongoDB.Collection(common.CheckpointCollection)
+	names, err = checkpoints.Indexes().CreateMany(ctx, repo.AuditCheckpointIndexes())
+	if err != nil {
+		fmt.Printf("Warning: Failed to create some checkpoint indexes: %v\n", err)
+		return
+	}
+
+	fmt.Printf("MongoDB checkpoint indexes created successfully: %v\n", names)
+
+	leaves := global.MongoDB.Collection(common.CheckpointLeafCollection)
+	names, err = leaves.Indexes().CreateMany(ctx, repo.AuditCheckpointLeafIndexes())
+	if err != nil {
+		fmt.Printf("Warning: Failed to create some checkpoint leaf indexes: %v\n", err)
+		return
+	}
+
+	fmt.Printf("MongoDB checkpoint leaf indexes created successfully: %v\n", names)
+
+	tombstones := global.MongoDB.Collection(common.ChainTombstoneCollection)
+	names, err = tombstones.Indexes().CreateMany(ctx, repo.ChainTombstoneIndexes())
+	if err != nil {
+		fmt.Printf("Warning: Failed to create some chain tombstone indexes: %v\n", err)
+		return
+	}
+
+	fmt.Printf("MongoDB chain tombstone indexes created successfully: %v\n", names)
+
+	quarantine := global.MongoDB.Collection(common.QuarantineCollection)
+	names, err = quarantine.Indexes().CreateMany(ctx, repo.QuarantineIndexes())
+	if err != nil {
+		fmt.Printf("Warning: Failed to create some quarantine indexes: %v\n", err)
+		return
+	}
+
+	fmt.Printf("MongoDB quarantine indexes created successfully: %v\n", names)
 }
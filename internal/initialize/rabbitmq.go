@@ -1,66 +1,81 @@
 package initialize
 
 import (
+	"context"
 	"fmt"
 
 	"event_service/global"
 	"event_service/internal/common"
+	"event_service/internal/topology"
 
 	"github.com/rabbitmq/amqp091-go"
 )
 
-func InitRabbitMQ() {
+// InitRabbitMQ connects to RabbitMQ and declares the topology, retrying
+// until ctx is done. A topology that conflicts with existing broker objects
+// is not retried.
+func InitRabbitMQ(ctx context.Context) error {
 	fmt.Println("Initializing RabbitMQ connection...")
 
-	cfg := global.Config.RabbitMQ
-
-	connStr := fmt.Sprintf("amqp://%s:%s@%s:%d/",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port)
+	return connectWithRetry(ctx, "rabbitmq", connectRabbitMQ)
+}
 
-	conn, err := amqp091.Dial(connStr)
+func connectRabbitMQ(ctx context.Context) error {
+	conn, err := amqp091.Dial(rabbitMQURL())
 	if err != nil {
-		panic(fmt.Errorf("%w: %v", common.ErrRabbitConnection, err))
+		return fmt.Errorf("%w: %v", common.ErrRabbitConnection, err)
 	}
 
-	// Create channel activity log queue
-	ch, err := conn.Channel()
-	if err != nil {
-		panic(fmt.Errorf("%w: %v", common.ErrRabbitChannel, err))
+	if err := declareTopology(conn); err != nil {
+		conn.Close()
+		return err
+	}
+
+	channels := make(map[string]*amqp091.Channel)
+	for _, name := range consumerNames() {
+		ch, err := conn.Channel()
+		if err != nil {
+			conn.Close()
+			return fmt.Errorf("%w: consumer %s: %v", common.ErrRabbitChannel, name, err)
+		}
+		channels[name] = ch
 	}
 
-	// Declare queue activity log queue
-	q, err := ch.QueueDeclare(
-		cfg.ActivityLogQueue, // name
-		true,                 // durable
-		false,                // delete when unused
-		false,                // exclusive
-		false,                // no-wait
-		nil,                  // arguments
-	)
+	global.RabbitMQ = conn
+	global.RabbitChannels = channels
+
+	fmt.Println("RabbitMQ connected successfully")
+	return nil
+}
+
+// declareTopology declares the configured exchanges, queues and bindings on
+// a dedicated channel, since a mismatch with an existing object closes the
+// channel it was declared on
+func declareTopology(conn *amqp091.Connection) error {
+	ch, err := conn.Channel()
 	if err != nil {
-		panic(fmt.Errorf("failed to declare queue: %v", err))
+		return fmt.Errorf("%w: %v", common.ErrRabbitChannel, err)
 	}
+	defer ch.Close()
+
+	t := topology.Resolve(global.Config.RabbitMQ, global.Config.Topology)
+	return topology.Declare(ch, t)
+}
 
-	err = ch.QueueBind(
-		q.Name,                    // queue name
-		cfg.ActivityLogBindingKey, // routing key
-		cfg.IAMExchange,           // exchange
-		false,
-		nil,
-	)
-	err = ch.QueueBind(
-		q.Name,                    // queue name
-		cfg.ActivityLogBindingKey, // routing key
-		cfg.AppStoreExchange,      // exchange
-		false,
-		nil,
-	)
+// DeclareTopology connects to RabbitMQ once and declares the topology
+func DeclareTopology() error {
+	conn, err := amqp091.Dial(rabbitMQURL())
 	if err != nil {
-		panic(fmt.Errorf("failed to bind queue: %v", err))
+		return fmt.Errorf("%w: %v", common.ErrRabbitConnection, err)
 	}
+	defer conn.Close()
 
-	global.RabbitMQ = conn
-	global.ActivityLogRabbitCh = ch
+	return declareTopology(conn)
+}
 
-	fmt.Printf("RabbitMQ connected successfully")
+func rabbitMQURL() string {
+	cfg := global.Config.RabbitMQ
+
+	return fmt.Sprintf("amqp://%s:%s@%s:%d/",
+		cfg.User, cfg.Password, cfg.Host, cfg.Port)
 }
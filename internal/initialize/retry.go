@@ -0,0 +1,109 @@
+package initialize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/health"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	defaultStartupTimeout    = 120 * time.Second
+	defaultInitialBackoff    = 500 * time.Millisecond
+	defaultMaxBackoff        = 10 * time.Second
+	defaultBackoffMultiplier = 2.0
+)
+
+type retryPolicy struct {
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+}
+
+// startupBudget returns the context bounding all dependency connection
+// attempts at startup.
+func startupBudget() (context.Context, context.CancelFunc) {
+	timeout := defaultStartupTimeout
+	if global.Config.Startup.TimeoutSeconds > 0 {
+		timeout = time.Duration(global.Config.Startup.TimeoutSeconds) * time.Second
+	}
+
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+func startupRetryPolicy() retryPolicy {
+	cfg := global.Config.Startup
+
+	policy := retryPolicy{
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		multiplier:     defaultBackoffMultiplier,
+	}
+	if cfg.InitialBackoffMs > 0 {
+		policy.initialBackoff = time.Duration(cfg.InitialBackoffMs) * time.Millisecond
+	}
+	if cfg.MaxBackoffMs > 0 {
+		policy.maxBackoff = time.Duration(cfg.MaxBackoffMs) * time.Millisecond
+	}
+	if cfg.BackoffMultiplier >= 1 {
+		policy.multiplier = cfg.BackoffMultiplier
+	}
+
+	return policy
+}
+
+// connectWithRetry calls connect until it succeeds or ctx is done, backing off
+// exponentially between attempts. Progress is published to the readiness
+// registry under the component name.
+func connectWithRetry(ctx context.Context, component string, connect func(ctx context.Context) error) error {
+	policy := startupRetryPolicy()
+	backoff := policy.initialBackoff
+	started := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		health.SetState(component, health.StateConnecting, attempt, nil)
+
+		err := connect(ctx)
+		if err == nil {
+			health.SetState(component, health.StateReady, attempt, nil)
+			return nil
+		}
+
+		fmt.Printf("%s connection attempt %d failed: %v\n", component, attempt, err)
+
+		if isPermanentStartupError(err) {
+			health.SetState(component, health.StateFailed, attempt, err)
+			return fmt.Errorf("%s: %w", component, err)
+		}
+		health.SetState(component, health.StateConnecting, attempt, err)
+
+		select {
+		case <-ctx.Done():
+			health.SetState(component, health.StateFailed, attempt, err)
+			return fmt.Errorf("%s: gave up after %d attempts in %s: %w",
+				component, attempt, time.Since(started).Round(time.Second), err)
+		case <-time.After(backoff):
+		}
+
+		backoff = time.Duration(float64(backoff) * policy.multiplier)
+		if backoff > policy.maxBackoff {
+			backoff = policy.maxBackoff
+		}
+	}
+}
+
+// isPermanentStartupError reports errors that retrying cannot fix, such as a
+// declared queue or exchange conflicting with the existing one
+func isPermanentStartupError(err error) bool {
+	var amqpErr *amqp091.Error
+	if errors.As(err, &amqpErr) {
+		return amqpErr.Code == amqp091.PreconditionFailed || amqpErr.Code == amqp091.AccessRefused
+	}
+
+	return false
+}
@@ -1,24 +1,91 @@
 package initialize
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"sync"
 
-func Run() {
+	"event_service/internal/health"
+)
+
+// Run initializes every component. Dependencies are retried within the
+// startup budget; the returned error aggregates every dependency that could
+// not be reached.
+func Run() error {
 	fmt.Println("Initializing Event Service components...")
 
 	LoadConfig()
 	fmt.Println("Configuration loaded")
 
-	InitMongoDB()
+	InitInstanceID()
+
+	if err := InitUpcasters(); err != nil {
+		return fmt.Errorf("startup failed: %w", err)
+	}
+
+	health.Register("mongodb")
+	health.Register("rabbitmq")
+	health.Register("consumers")
+
+	InitHTTPServer()
+	fmt.Println("HTTP server started")
+
+	if err := connectDependencies(); err != nil {
+		return fmt.Errorf("startup failed: %w", err)
+	}
 	fmt.Println("MongoDB connected")
+	fmt.Println("RabbitMQ connected")
 
 	CreateMongoDBIndexes()
 	fmt.Println("MongoDB indexes created")
 
-	InitRabbitMQ()
-	fmt.Println("RabbitMQ connected")
+	InitCircuitBreaker()
+
+	if err := InitEncryption(); err != nil {
+		return fmt.Errorf("startup failed: %w", err)
+	}
 
-	InitConsumers()
+	if err := InitSpool(); err != nil {
+		return fmt.Errorf("startup failed: %w", err)
+	}
+
+	if err := InitScheduler(); err != nil {
+		return fmt.Errorf("startup failed: %w", err)
+	}
+
+	// The consumer manager reports the consumers' state once they started
+	if err := InitConsumers(); err != nil {
+		health.SetState("consumers", health.StateFailed, 0, err)
+		return fmt.Errorf("startup failed: %w", err)
+	}
 	fmt.Println("Consumers initialized")
 
 	fmt.Println("All components initialized successfully")
+	return nil
+}
+
+// connectDependencies connects to MongoDB and RabbitMQ concurrently, sharing
+// one startup budget.
+func connectDependencies() error {
+	ctx, cancel := startupBudget()
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mongoErr error
+		amqpErr  error
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		mongoErr = InitMongoDB(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		amqpErr = InitRabbitMQ(ctx)
+	}()
+	wg.Wait()
+
+	return errors.Join(mongoErr, amqpErr)
 }
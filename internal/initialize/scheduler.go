@@ -0,0 +1,57 @@
+package initialize
+
+import (
+	"context"
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/jobs"
+	"event_service/internal/scheduler"
+)
+
+var Scheduler *scheduler.Scheduler
+
+// registerJobs makes the built-in jobs available to scheduler.jobs
+func registerJobs() {
+	scheduler.RegisterJob("index_verification", jobs.NewIndexVerificationJob)
+	scheduler.RegisterJob("dlq_alerts", jobs.NewDLQAlertsJob)
+	scheduler.RegisterJob("purge", jobs.NewPurgeJob)
+	scheduler.RegisterJob("rollup", jobs.NewRollupJob)
+	scheduler.RegisterJob("data_key_rotation", jobs.NewDataKeyRotationJob)
+	scheduler.RegisterJob("audit_checkpoint", jobs.NewAuditCheckpointJob)
+}
+
+// NewScheduler builds the scheduler for the configured jobs without
+// starting it
+func NewScheduler() (*scheduler.Scheduler, error) {
+	registerJobs()
+	return scheduler.New()
+}
+
+// InitScheduler starts running the configured jobs when the scheduler is
+// enabled
+func InitScheduler() error {
+	if !global.Config.Scheduler.Enabled {
+		fmt.Println("Scheduler disabled")
+		return nil
+	}
+
+	s, err := NewScheduler()
+	if err != nil {
+		return fmt.Errorf("failed to create scheduler: %w", err)
+	}
+
+	s.Start()
+	Scheduler = s
+	return nil
+}
+
+func stopScheduler(ctx context.Context) {
+	if Scheduler == nil {
+		return
+	}
+
+	if err := Scheduler.Stop(ctx); err != nil {
+		fmt.Printf("Error stopping scheduler: %v\n", err)
+	}
+}
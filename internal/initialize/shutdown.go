@@ -0,0 +1,102 @@
+package initialize
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/health"
+	"event_service/internal/lease"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// WaitForShutdown blocks until SIGINT or SIGTERM is received and then runs
+// the shutdown sequence, returning control to the caller once it is done.
+func WaitForShutdown() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(c)
+
+	sig := <-c
+	fmt.Printf("Received %s, shutting down...\n", sig)
+
+	Shutdown()
+}
+
+// Shutdown stops consumers from receiving new deliveries, waits for in-flight
+// messages up to the configured deadline (requeueing whatever is left), and
+// then stops scheduled jobs, closes RabbitMQ and the spool, releases leases
+// and closes MongoDB.
+func Shutdown() {
+	timeout := defaultShutdownTimeout
+	if global.Config != nil && global.Config.Server.ShutdownTimeoutSeconds > 0 {
+		timeout = time.Duration(global.Config.Server.ShutdownTimeoutSeconds) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	health.SetState("consumers", health.StateStopping, 0, nil)
+
+	if ConsumerManager != nil {
+		err := ConsumerManager.StopAll(ctx)
+		if err != nil {
+			fmt.Printf("Error stopping consumers: %v\n", err)
+		}
+	}
+
+	stopScheduler(ctx)
+	closeRabbitMQ()
+	closeSpool()
+
+	releaseLeases()
+	DisconnectMongoDB()
+	stopHTTPServer()
+
+	fmt.Println("Graceful shutdown completed")
+}
+
+func closeRabbitMQ() {
+	for name, ch := range global.RabbitChannels {
+		if err := ch.Close(); err != nil {
+			fmt.Printf("Error closing RabbitMQ channel for consumer %s: %v\n", name, err)
+		}
+	}
+	if global.RabbitMQ != nil {
+		if err := global.RabbitMQ.Close(); err != nil {
+			fmt.Printf("Error closing RabbitMQ connection: %v\n", err)
+		}
+	}
+}
+
+// releaseLeases hands leases held by this instance over before MongoDB goes
+// away, so other replicas don't wait for them to expire
+func releaseLeases() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := lease.StopAll(ctx); err != nil {
+		fmt.Printf("Error releasing leases: %v\n", err)
+	}
+}
+
+// DisconnectMongoDB closes the MongoDB client, if connected
+func DisconnectMongoDB() {
+	if global.MongoClient == nil {
+		return
+	}
+
+	// The drain may have used up the shutdown deadline, so the disconnect
+	// gets its own budget
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := global.MongoClient.Disconnect(ctx); err != nil {
+		fmt.Printf("Error disconnecting MongoDB: %v\n", err)
+	}
+}
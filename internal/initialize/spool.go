@@ -0,0 +1,78 @@
+package initialize
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/metrics"
+	"event_service/internal/services"
+	"event_service/internal/spool"
+)
+
+var SpoolDrainer *services.SpoolDrainer
+
+// InitSpool opens the local disk spool and starts the drainer that replays
+// spooled events into MongoDB. It is a no-op when the spool is disabled.
+func InitSpool() error {
+	cfg := global.Config.Spool
+	if !cfg.Enabled {
+		return nil
+	}
+
+	fmt.Println("Initializing local spool...")
+
+	fsyncPolicy := cfg.FsyncPolicy
+	if fsyncPolicy == "" {
+		fsyncPolicy = spool.FsyncInterval
+	}
+
+	s, err := spool.Open(spool.Options{
+		Directory:       cfg.Directory,
+		SegmentMaxBytes: cfg.SegmentMaxBytes,
+		MaxTotalBytes:   cfg.MaxTotalBytes,
+		FsyncPolicy:     fsyncPolicy,
+		FsyncInterval:   time.Duration(cfg.FsyncIntervalMs) * time.Millisecond,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open spool: %v", err)
+	}
+
+	global.Spool = s
+
+	metrics.NewGaugeFunc("event_service_spool_depth_records", "Activity logs waiting in the local spool", func() float64 {
+		records, _ := s.Depth()
+		return float64(records)
+	})
+	metrics.NewGaugeFunc("event_service_spool_depth_bytes", "Bytes waiting in the local spool", func() float64 {
+		_, bytes := s.Depth()
+		return float64(bytes)
+	})
+
+	drainInterval := time.Duration(cfg.DrainIntervalSeconds) * time.Second
+	if drainInterval <= 0 {
+		drainInterval = 10 * time.Second
+	}
+
+	SpoolDrainer = services.NewSpoolDrainer(s, drainInterval)
+	SpoolDrainer.Start(context.Background())
+
+	records, bytes := s.Depth()
+	fmt.Printf("Local spool ready at %s (%d records, %d bytes pending)\n", cfg.Directory, records, bytes)
+	return nil
+}
+
+// closeSpool stops the drainer and flushes the active segment. Consumers must
+// already be stopped so nothing is appended afterwards.
+func closeSpool() {
+	if SpoolDrainer != nil {
+		SpoolDrainer.Stop()
+	}
+
+	if global.Spool != nil {
+		if err := global.Spool.Close(); err != nil {
+			fmt.Printf("Error closing spool: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package initialize
+
+import (
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/upcast"
+)
+
+// InitUpcasters registers the payload upcasters declared in schema.upcasters.
+// Upcasters written in Go are registered here too, with upcast.Register.
+func InitUpcasters() error {
+	for i, rule := range global.Config.Schema.Upcasters {
+		upcaster, err := upcast.FromRule(rule)
+		if err != nil {
+			return fmt.Errorf("schema upcaster %d: %v", i+1, err)
+		}
+		upcast.Register(rule.Topic, rule.FromVersion, upcaster)
+	}
+
+	if err := upcast.Verify(); err != nil {
+		return err
+	}
+
+	for _, topic := range upcast.Topics() {
+		fmt.Printf("Payload upcasters registered for %s (current schema version %d)\n", topic, upcast.CurrentVersion(topic))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/internal/lease"
+	"event_service/internal/scheduler"
+	"event_service/internal/services"
+)
+
+// NewAuditCheckpointJob signs a Merkle root over the activity logs processed
+// since the previous checkpoint. The first checkpoint covers the last
+// window_minutes (default 15); settle_seconds (default 60) keeps logs still
+// being written out of the window.
+func NewAuditCheckpointJob(params map[string]interface{}) (scheduler.JobFunc, error) {
+	windowMinutes, err := scheduler.IntParam(params, "window_minutes", 15)
+	if err != nil {
+		return nil, err
+	}
+	if windowMinutes <= 0 {
+		return nil, fmt.Errorf("param window_minutes must be positive")
+	}
+
+	settleSeconds, err := scheduler.IntParam(params, "settle_seconds", 60)
+	if err != nil {
+		return nil, err
+	}
+	if settleSeconds < 0 {
+		return nil, fmt.Errorf("param settle_seconds must not be negative")
+	}
+
+	return func(ctx context.Context, fence lease.Fence) error {
+		_, err := services.NewCheckpointService().CreateCheckpoint(ctx,
+			time.Duration(windowMinutes)*time.Minute,
+			time.Duration(settleSeconds)*time.Second,
+			fence,
+		)
+		return err
+	}, nil
+}
@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/internal/encryption"
+	"event_service/internal/lease"
+	"event_service/internal/scheduler"
+)
+
+// NewDataKeyRotationJob creates a new data key once the newest one is older
+// than the max_age_days param (default 90). Existing documents keep their
+// key; only new documents use the new one.
+func NewDataKeyRotationJob(params map[string]interface{}) (scheduler.JobFunc, error) {
+	maxAgeDays, err := scheduler.IntParam(params, "max_age_days", 90)
+	if err != nil {
+		return nil, err
+	}
+	if maxAgeDays <= 0 {
+		return nil, fmt.Errorf("param max_age_days must be positive")
+	}
+
+	return func(ctx context.Context, fence lease.Fence) error {
+		encryptor := encryption.Current()
+		if encryptor == nil {
+			return fmt.Errorf("field encryption is disabled")
+		}
+
+		keys, err := encryptor.Keyring().Keys(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(keys) > 0 {
+			newest := keys[len(keys)-1]
+			if time.Since(newest.CreatedAt) < time.Duration(maxAgeDays)*24*time.Hour {
+				fmt.Printf("Data key %s is %d days old, no rotation needed\n", newest.ID, int(time.Since(newest.CreatedAt).Hours()/24))
+				return nil
+			}
+		}
+
+		if err := fence.Check(ctx); err != nil {
+			return err
+		}
+		_, err = encryptor.Keyring().Rotate(ctx)
+		return err
+	}, nil
+}
@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"event_service/global"
+	"event_service/internal/lease"
+	"event_service/internal/metrics"
+	"event_service/internal/scheduler"
+	"event_service/internal/topology"
+)
+
+var dlqDepth = metrics.NewGauge("event_service_dead_letter_queue_messages",
+	"Messages waiting in a consumer's dead letter queue", "queue")
+
+// NewDLQAlertsJob checks the depth of every consumer's dead letter queue
+// through the management API and fails when one holds more than the
+// threshold param (default 0), so the alert shows up in the job status
+func NewDLQAlertsJob(params map[string]interface{}) (scheduler.JobFunc, error) {
+	threshold, err := scheduler.IntParam(params, "threshold", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, _ lease.Fence) error {
+		cfg := global.Config.RabbitMQ
+		if cfg.ManagementURL == "" {
+			return errors.New("rabbitmq.management_url is required to check dead letter queues")
+		}
+		client := topology.NewManagementClient(cfg.ManagementURL, "/", cfg.User, cfg.Password)
+
+		queues := make(map[string]bool)
+		for _, consumer := range cfg.Consumers {
+			if consumer.DeadLetterQueue != "" {
+				queues[consumer.DeadLetterQueue] = true
+			}
+		}
+
+		names := make([]string, 0, len(queues))
+		for queue := range queues {
+			names = append(names, queue)
+		}
+		sort.Strings(names)
+
+		var alerts, errs []string
+		for _, queue := range names {
+			depth, err := client.QueueDepth(ctx, queue)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", queue, err))
+				continue
+			}
+
+			dlqDepth.Set(float64(depth), queue)
+			if depth > threshold {
+				fmt.Printf("ALERT: dead letter queue %s holds %d messages\n", queue, depth)
+				alerts = append(alerts, fmt.Sprintf("%s=%d", queue, depth))
+			}
+		}
+
+		if len(errs) > 0 {
+			return fmt.Errorf("failed to check dead letter queues: %s", strings.Join(errs, "; "))
+		}
+		if len(alerts) > 0 {
+			return fmt.Errorf("dead letter queues over threshold %d: %s", threshold, strings.Join(alerts, ", "))
+		}
+
+		return nil
+	}, nil
+}
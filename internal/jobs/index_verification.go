@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/lease"
+	"event_service/internal/repo"
+	"event_service/internal/scheduler"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NewIndexVerificationJob checks that the activity log collection has every
+// expected index and recreates the missing ones
+func NewIndexVerificationJob(params map[string]interface{}) (scheduler.JobFunc, error) {
+	return func(ctx context.Context, fence lease.Fence) error {
+		collection := global.MongoDB.Collection(global.Config.MongoDB.ActivityLogCollection)
+
+		existing, err := indexNames(ctx, collection)
+		if err != nil {
+			return err
+		}
+
+		var missing []mongo.IndexModel
+		var names []string
+		for _, index := range repo.ActivityLogIndexes() {
+			if name := repo.IndexName(index); !existing[name] {
+				missing = append(missing, index)
+				names = append(names, name)
+			}
+		}
+
+		if len(missing) == 0 {
+			fmt.Println("All activity log indexes present")
+			return nil
+		}
+
+		if err := fence.Check(ctx); err != nil {
+			return err
+		}
+		fmt.Printf("Recreating missing activity log indexes: %v\n", names)
+		if _, err := collection.Indexes().CreateMany(ctx, missing); err != nil {
+			return fmt.Errorf("failed to recreate indexes %v: %w", names, err)
+		}
+
+		return nil
+	}, nil
+}
+
+func indexNames(ctx context.Context, collection *mongo.Collection) (map[string]bool, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+
+	var indexes []bson.M
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for _, index := range indexes {
+		if name, ok := index["name"].(string); ok {
+			names[name] = true
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/lease"
+	"event_service/internal/repo"
+	"event_service/internal/scheduler"
+	"event_service/internal/services"
+)
+
+// RetentionStart returns the first UTC day no enabled purge job deletes
+// from, and false when no purge job is enabled. Days before it may have
+// lost logs to the purge, so their rollups must not be rebuilt.
+func RetentionStart() (time.Time, bool) {
+	var start time.Time
+	found := false
+
+	for name, job := range global.Config.Scheduler.Jobs {
+		jobName := job.Job
+		if jobName == "" {
+			jobName = name
+		}
+		if !job.Enabled || jobName != "purge" {
+			continue
+		}
+
+		retentionDays, err := scheduler.IntParam(job.Params, "retention_days", 365)
+		if err != nil || retentionDays <= 0 {
+			continue
+		}
+
+		// The day the cutoff falls in is partly purged already
+		cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
+		day := cutoff.Truncate(24*time.Hour).AddDate(0, 0, 1)
+		if !found || day.After(start) {
+			start, found = day, true
+		}
+	}
+
+	return start, found
+}
+
+// NewPurgeJob deletes activity logs older than the retention_days param,
+// except those under a legal hold
+func NewPurgeJob(params map[string]interface{}) (scheduler.JobFunc, error) {
+	retentionDays, err := scheduler.IntParam(params, "retention_days", 365)
+	if err != nil {
+		return nil, err
+	}
+	if retentionDays <= 0 {
+		return nil, fmt.Errorf("param retention_days must be positive")
+	}
+
+	return func(ctx context.Context, fence lease.Fence) error {
+		before := time.Now().AddDate(0, 0, -retentionDays)
+
+		holds, err := services.NewLegalHoldService().Scope(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := fence.Check(ctx); err != nil {
+			return err
+		}
+		deleted, held, err := repo.NewActivityLogRetentionRepository().DeleteBefore(ctx, before, holds)
+		if err != nil {
+			return err
+		}
+		services.LegalHeldTotal.Add(float64(held), "purge")
+
+		fmt.Printf("Purged %d activity logs processed before %s, kept %d under legal hold\n", deleted, before.Format(time.RFC3339), held)
+		return nil
+	}, nil
+}
@@ -0,0 +1,43 @@
+package lease
+
+import (
+	"context"
+	"fmt"
+
+	"event_service/internal/common"
+	"event_service/internal/repo"
+)
+
+// Fence is the fencing token of one lease term. Jobs check it right before
+// their writes, so a holder that lost the lease without noticing (paused,
+// partitioned) does not write over the new holder. The zero Fence belongs
+// to jobs that run on every instance and always passes.
+type Fence struct {
+	name       string
+	owner      string
+	token      int64
+	repository repo.LeaseRepository
+}
+
+// Token returns the fencing token, 0 for the zero Fence
+func (f Fence) Token() int64 {
+	return f.token
+}
+
+// Check returns ErrStaleLease unless the lease is still held with this
+// fence's token
+func (f Fence) Check(ctx context.Context) error {
+	if f.repository == nil {
+		return nil
+	}
+
+	holds, err := f.repository.Holds(ctx, f.name, f.owner, f.token)
+	if err != nil {
+		return err
+	}
+	if !holds {
+		return fmt.Errorf("%w: %s token %d", common.ErrStaleLease, f.name, f.token)
+	}
+
+	return nil
+}
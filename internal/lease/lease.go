@@ -0,0 +1,362 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/health"
+	"event_service/internal/metrics"
+	"event_service/internal/repo"
+)
+
+const (
+	defaultTTL           = 15 * time.Second
+	defaultRenewInterval = 5 * time.Second
+	defaultMaxClockSkew  = time.Second
+)
+
+var leaseHeld = metrics.NewGauge("event_service_lease_held",
+	"1 if this instance holds the lease, 0 otherwise", "lease")
+
+// Status describes who holds a lease, as seen by this instance
+type Status struct {
+	Name      string    `json:"name"`
+	Leader    bool      `json:"leader"`
+	Holder    string    `json:"holder,omitempty"`
+	Token     int64     `json:"token,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// Lease elects one instance to run a singleton job. Every instance
+// campaigns for the lease in the background; the holder renews it and the
+// others take it over once it expires.
+type Lease struct {
+	name          string
+	owner         string
+	ttl           time.Duration
+	renewInterval time.Duration
+	maxClockSkew  time.Duration
+	repository    repo.LeaseRepository
+
+	mu        sync.Mutex
+	leader    bool
+	token     int64
+	holder    string
+	expiresAt time.Time
+	leaderCtx context.Context
+	stepDown  context.CancelFunc
+
+	stopChannel chan struct{}
+	done        chan struct{}
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]*Lease)
+)
+
+// New creates the lease name for this instance. It does nothing until
+// Start is called.
+func New(name string) *Lease {
+	cfg := global.Config.LeaderElection
+
+	ttl := time.Duration(cfg.LeaseTTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	renewInterval := time.Duration(cfg.RenewIntervalSeconds) * time.Second
+	if renewInterval <= 0 {
+		renewInterval = defaultRenewInterval
+	}
+	if renewInterval >= ttl {
+		renewInterval = ttl / 3
+	}
+	maxClockSkew := time.Duration(cfg.MaxClockSkewMs) * time.Millisecond
+	if maxClockSkew <= 0 {
+		maxClockSkew = defaultMaxClockSkew
+	}
+
+	return &Lease{
+		name:          name,
+		owner:         global.InstanceID,
+		ttl:           ttl,
+		renewInterval: renewInterval,
+		maxClockSkew:  maxClockSkew,
+		repository:    repo.NewLeaseRepository(),
+		stopChannel:   make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start begins campaigning for the lease
+func (l *Lease) Start() {
+	registryMu.Lock()
+	registry[l.name] = l
+	registryMu.Unlock()
+
+	go l.campaign()
+}
+
+// Stop stops campaigning and releases the lease if held, so another
+// instance can take over without waiting for it to expire
+func (l *Lease) Stop(ctx context.Context) error {
+	close(l.stopChannel)
+	<-l.done
+
+	l.mu.Lock()
+	wasLeader, token := l.leader, l.token
+	l.mu.Unlock()
+
+	l.setLeader(false, 0, "", time.Time{})
+
+	registryMu.Lock()
+	delete(registry, l.name)
+	registryMu.Unlock()
+	publishStatus()
+
+	if !wasLeader {
+		return nil
+	}
+
+	if err := l.repository.Release(ctx, l.name, l.owner, token); err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", l.name, err)
+	}
+	fmt.Printf("Released lease %s\n", l.name)
+	return nil
+}
+
+// IsLeader reports whether this instance currently holds the lease
+func (l *Lease) IsLeader() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.leader
+}
+
+// Token returns the fencing token of the current term, 0 when not leader.
+// Writes guarded by the lease should carry it so a stale holder's writes
+// can be rejected.
+func (l *Lease) Token() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.token
+}
+
+// Run runs job if this instance holds the lease and reports whether it ran.
+// The job's context is cancelled if leadership is lost while it runs, and
+// the job gets the fence of the current term to check before its writes.
+func (l *Lease) Run(ctx context.Context, job func(ctx context.Context, fence Fence) error) (bool, error) {
+	l.mu.Lock()
+	if !l.leader {
+		l.mu.Unlock()
+		return false, nil
+	}
+	leaderCtx, token := l.leaderCtx, l.token
+	l.mu.Unlock()
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(leaderCtx, cancel)
+	defer stop()
+
+	fence := Fence{name: l.name, owner: l.owner, token: token, repository: l.repository}
+	return true, job(jobCtx, fence)
+}
+
+// RunOnce takes the lease, runs job under it and releases it, for one-off
+// runs outside the scheduler (e.g. the CLI). It reports false without
+// running job when another instance holds the lease. The lease must not
+// have been started.
+func (l *Lease) RunOnce(ctx context.Context, job func(ctx context.Context, fence Fence) error) (bool, error) {
+	acquired, ok, err := l.repository.Acquire(ctx, l.name, l.owner, l.ttl)
+	if err != nil || !ok {
+		return false, err
+	}
+	l.setLeader(true, acquired.Token, acquired.Owner, acquired.ExpiresAt)
+
+	// Keep renewing while the job runs
+	go l.campaign()
+	defer func() {
+		if err := l.Stop(context.Background()); err != nil {
+			fmt.Printf("Error releasing lease %s: %v\n", l.name, err)
+		}
+	}()
+
+	return l.Run(ctx, job)
+}
+
+func (l *Lease) Status() Status {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return Status{
+		Name:      l.name,
+		Leader:    l.leader,
+		Holder:    l.holder,
+		Token:     l.token,
+		ExpiresAt: l.expiresAt,
+	}
+}
+
+func (l *Lease) campaign() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		l.tick()
+
+		select {
+		case <-l.stopChannel:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *Lease) tick() {
+	l.mu.Lock()
+	leader, token, expiresAt := l.leader, l.token, l.expiresAt
+	l.mu.Unlock()
+
+	// Another replica may take the lease once it expires by its clock, so
+	// a leader's renewal must finish before expiry minus the skew
+	deadline := time.Now().Add(l.renewInterval)
+	if leader {
+		deadline = minTime(deadline, expiresAt.Add(-l.maxClockSkew))
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	if leader {
+		// Count the TTL from before the call; the stored expiry is no earlier
+		renewedAt := time.Now()
+		renewed, err := l.repository.Renew(ctx, l.name, l.owner, token, l.ttl)
+		switch {
+		case err != nil && !time.Now().Add(l.renewInterval+l.maxClockSkew).Before(expiresAt):
+			// The next attempt would come too late: step down while the
+			// lease is still ours rather than overlap with the next holder
+			fmt.Printf("Lost lease %s: renewal failed too close to expiry: %v\n", l.name, err)
+			l.setLeader(false, 0, "", time.Time{})
+		case err != nil:
+			fmt.Printf("Error renewing lease %s: %v\n", l.name, err)
+		case !renewed:
+			fmt.Printf("Lost lease %s: taken over by another instance\n", l.name)
+			l.setLeader(false, 0, "", time.Time{})
+		default:
+			l.setLeader(true, token, l.owner, renewedAt.Add(l.ttl))
+		}
+		return
+	}
+
+	acquired, ok, err := l.repository.Acquire(ctx, l.name, l.owner, l.ttl)
+	if err != nil {
+		fmt.Printf("Error acquiring lease %s: %v\n", l.name, err)
+		return
+	}
+	if ok {
+		fmt.Printf("Acquired lease %s (token %d)\n", l.name, acquired.Token)
+		l.setLeader(true, acquired.Token, acquired.Owner, acquired.ExpiresAt)
+		return
+	}
+
+	current, err := l.repository.Get(ctx, l.name)
+	if err != nil {
+		fmt.Printf("Error reading lease %s: %v\n", l.name, err)
+		return
+	}
+	if current != nil {
+		l.setLeader(false, 0, current.Owner, current.ExpiresAt)
+	}
+}
+
+func (l *Lease) setLeader(leader bool, token int64, holder string, expiresAt time.Time) {
+	l.mu.Lock()
+
+	switch {
+	case leader && !l.leader:
+		l.leaderCtx, l.stepDown = context.WithCancel(context.Background())
+	case !leader && l.leader:
+		l.stepDown()
+	}
+
+	l.leader = leader
+	l.token = token
+	l.holder = holder
+	l.expiresAt = expiresAt
+	l.mu.Unlock()
+
+	value := 0.0
+	if leader {
+		value = 1
+	}
+	leaseHeld.Set(value, l.name)
+	publishStatus()
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// Statuses returns the status of every running lease, sorted by name
+func Statuses() []Status {
+	registryMu.Lock()
+	leases := make([]*Lease, 0, len(registry))
+	for _, l := range registry {
+		leases = append(leases, l)
+	}
+	registryMu.Unlock()
+
+	statuses := make([]Status, 0, len(leases))
+	for _, l := range leases {
+		statuses = append(statuses, l.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	return statuses
+}
+
+// publishStatus shows who holds each lease on the readiness endpoint
+func publishStatus() {
+	details := make(map[string]string)
+	for _, status := range Statuses() {
+		switch {
+		case status.Leader:
+			details[status.Name] = fmt.Sprintf("leader (token %d)", status.Token)
+		case status.Holder != "":
+			details[status.Name] = "follower of " + status.Holder
+		default:
+			details[status.Name] = "follower"
+		}
+	}
+	health.SetDetails("leader_election", details)
+}
+
+// StopAll stops every running lease, releasing the ones this instance holds
+func StopAll(ctx context.Context) error {
+	registryMu.Lock()
+	leases := make([]*Lease, 0, len(registry))
+	for _, l := range registry {
+		leases = append(leases, l)
+	}
+	registryMu.Unlock()
+
+	var errs []error
+	for _, l := range leases {
+		if err := l.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
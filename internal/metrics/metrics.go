@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	typeCounter = "counter"
+	typeGauge   = "gauge"
+)
+
+// metric is a named family of values keyed by label pairs
+type metric struct {
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]float64
+	fn     func() float64
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]*metric)
+)
+
+func register(name, help, metricType string, labelNames []string) *metric {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if existing, exists := registry[name]; exists {
+		return existing
+	}
+
+	m := &metric{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		values:     make(map[string]float64),
+	}
+	registry[name] = m
+	return m
+}
+
+func (m *metric) add(delta float64, labelValues []string) {
+	key := m.labelKey(labelValues)
+
+	m.mu.Lock()
+	m.values[key] += delta
+	m.mu.Unlock()
+}
+
+func (m *metric) set(value float64, labelValues []string) {
+	key := m.labelKey(labelValues)
+
+	m.mu.Lock()
+	m.values[key] = value
+	m.mu.Unlock()
+}
+
+func (m *metric) labelKey(labelValues []string) string {
+	if len(labelValues) != len(m.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d labels, got %d", m.name, len(m.labelNames), len(labelValues)))
+	}
+
+	pairs := make([]string, len(labelValues))
+	for i, value := range labelValues {
+		pairs[i] = fmt.Sprintf("%s=%q", m.labelNames[i], value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Counter is a monotonically increasing value
+type Counter struct{ m *metric }
+
+// NewCounter registers (or returns the already registered) counter
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{m: register(name, help, typeCounter, labelNames)}
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.m.add(1, labelValues)
+}
+
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	c.m.add(delta, labelValues)
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct{ m *metric }
+
+// NewGauge registers (or returns the already registered) gauge
+func NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{m: register(name, help, typeGauge, labelNames)}
+}
+
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.m.set(value, labelValues)
+}
+
+func (g *Gauge) Add(delta float64, labelValues ...string) {
+	g.m.add(delta, labelValues)
+}
+
+// NewGaugeFunc registers a label-less gauge whose value is computed on scrape
+func NewGaugeFunc(name, help string, fn func() float64) {
+	m := register(name, help, typeGauge, nil)
+
+	m.mu.Lock()
+	m.fn = fn
+	m.mu.Unlock()
+}
+
+// WriteText renders every registered metric in the Prometheus text format
+func WriteText(w io.Writer) error {
+	registryMu.Lock()
+	all := make([]*metric, 0, len(registry))
+	for _, m := range registry {
+		all = append(all, m)
+	}
+	registryMu.Unlock()
+
+	sort.Slice(all, func(i, j int) bool { return all[i].name < all[j].name })
+
+	for _, m := range all {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.metricType); err != nil {
+			return err
+		}
+
+		m.mu.Lock()
+		if m.fn != nil {
+			m.values[""] = m.fn()
+		}
+		keys := make([]string, 0, len(m.values))
+		for key := range m.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		lines := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if key == "" {
+				lines = append(lines, fmt.Sprintf("%s %v\n", m.name, m.values[key]))
+			} else {
+				lines = append(lines, fmt.Sprintf("%s{%s} %v\n", m.name, key, m.values[key]))
+			}
+		}
+		m.mu.Unlock()
+
+		for _, line := range lines {
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -12,7 +12,32 @@ type ActivityLog struct {
 	Topic         string                 `bson:"topic" json:"topic"`
 	SourceService string                 `bson:"sourceService" json:"sourceService"`
 	Timestamp     time.Time              `bson:"timestamp" json:"timestamp"`
+	Subject       string                 `bson:"subject,omitempty" json:"subject,omitempty"`
 	Payload       map[string]interface{} `bson:"payload" json:"payload"`
+	Extensions    map[string]interface{} `bson:"extensions,omitempty" json:"extensions,omitempty"` // CloudEvents extension attributes, as received
+	Metadata      map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`     // delivery metadata: fields filled from AMQP properties, kept headers
 	ProcessedAt   time.Time              `bson:"processedAt" json:"processedAt"`
 	Version       int                    `bson:"version" json:"version"`
+	SchemaVersion int                    `bson:"schemaVersion,omitempty" json:"schemaVersion,omitempty"` // payload shape version; absent on logs stored before versioning, read as 1
+	UpcastFrom    int                    `bson:"upcastFrom,omitempty" json:"upcastFrom,omitempty"`       // version the event arrived in, when upcast at ingest
+	Redactions    []string               `bson:"redactions,omitempty" json:"redactions,omitempty"`       // redaction rules applied to the payload
+	Encryption    *EncryptionInfo        `bson:"encryption,omitempty" json:"encryption,omitempty"`
+	Chain         *ChainLink             `bson:"chain,omitempty" json:"chain,omitempty"`
+}
+
+// ChainLink places a log in the hash chain of its partition. Hash covers the
+// content hash, the sequence number and the previous log's hash, so editing,
+// removing or reordering a log breaks the chain after it.
+type ChainLink struct {
+	Partition   string `bson:"partition" json:"partition"`
+	Seq         int64  `bson:"seq" json:"seq"`
+	ContentHash string `bson:"contentHash" json:"contentHash"`
+	PrevHash    string `bson:"prevHash" json:"prevHash"`
+	Hash        string `bson:"hash" json:"hash"`
+}
+
+// EncryptionInfo names the data key and the payload paths encrypted with it
+type EncryptionInfo struct {
+	KeyID string   `bson:"keyId" json:"keyId"`
+	Paths []string `bson:"paths" json:"paths"`
 }
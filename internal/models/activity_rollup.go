@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ActivityRollup counts the activity logs of one day (UTC) per topic,
+// source service and workspace
+type ActivityRollup struct {
+	Day           time.Time `bson:"day" json:"day"`
+	Topic         string    `bson:"topic" json:"topic"`
+	SourceService string    `bson:"sourceService" json:"sourceService"`
+	WorkspaceID   string    `bson:"workspaceId" json:"workspaceId"` // empty for events outside a workspace
+	Count         int64     `bson:"count" json:"count"`
+	UpdatedAt     time.Time `bson:"updatedAt" json:"updatedAt"`
+}
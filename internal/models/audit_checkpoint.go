@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditCheckpoint commits to the activity logs processed in [From, To): the
+// Merkle root over their leaves, ordered by eventId, signed with the
+// service's Ed25519 key. Each checkpoint names the previous one, so
+// checkpoints form a chain too.
+type AuditCheckpoint struct {
+	ID        string    `bson:"_id" json:"id"`
+	From      time.Time `bson:"from" json:"from"`
+	To        time.Time `bson:"to" json:"to"`
+	Leaves    int64     `bson:"leaves" json:"leaves"`
+	Root      string    `bson:"root" json:"root"` // hex
+	PrevID    string    `bson:"prevId,omitempty" json:"prevId,omitempty"`
+	PrevRoot  string    `bson:"prevRoot,omitempty" json:"prevRoot,omitempty"`
+	KeyID     string    `bson:"keyId" json:"keyId"`
+	PublicKey string    `bson:"publicKey" json:"publicKey"` // base64 Ed25519 public key
+	Signature string    `bson:"signature" json:"signature"` // base64 Ed25519 signature of the signed message
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+// AuditCheckpointLeaf is one leaf of a checkpoint as it was signed: the log's
+// event id and content hash at signing time. Proofs are built from these
+// rather than from the live logs, so erasing, purging or re-encrypting one
+// log leaves the rest of its window provable.
+type AuditCheckpointLeaf struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	CheckpointID string             `bson:"checkpointId" json:"checkpointId"`
+	Index        int64              `bson:"index" json:"index"`
+	EventID      string             `bson:"eventId" json:"eventId"`
+	ContentHash  string             `bson:"contentHash" json:"contentHash"`
+}
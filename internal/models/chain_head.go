@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ChainHead is the newest link of a hash chain partition. It outlives the
+// log it points at, so deleting the newest logs can still be detected.
+type ChainHead struct {
+	Partition string    `bson:"_id" json:"partition"`
+	Seq       int64     `bson:"seq" json:"seq"`
+	Hash      string    `bson:"hash" json:"hash"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
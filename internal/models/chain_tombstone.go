@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Chain tombstone actions
+const (
+	TombstoneAnonymized = "anonymized" // user erasure rewrote the log
+	TombstoneErased     = "erased"     // user erasure deleted the log
+	TombstonePurged     = "purged"     // retention purge deleted the log
+)
+
+// ChainTombstone records a log that was legitimately rewritten or deleted.
+// It keeps the content hash the log was stored with and, for chained logs,
+// the link the log had, so audit verify can walk the chain through deleted
+// logs and tell them apart from tampering. The first action's link and
+// content hash are kept when a log is rewritten again or deleted later.
+type ChainTombstone struct {
+	EventID        string    `bson:"eventId" json:"eventId"`
+	Partition      string    `bson:"partition,omitempty" json:"partition,omitempty"`
+	Seq            int64     `bson:"seq,omitempty" json:"seq,omitempty"`
+	ContentHash    string    `bson:"contentHash" json:"contentHash"` // of the log as it was stored
+	PrevHash       string    `bson:"prevHash,omitempty" json:"prevHash,omitempty"`
+	Hash           string    `bson:"hash,omitempty" json:"hash,omitempty"`
+	Action         string    `bson:"action" json:"action"`
+	NewContentHash string    `bson:"newContentHash,omitempty" json:"newContentHash,omitempty"` // anonymized only: of the rewritten log
+	Reference      string    `bson:"reference" json:"reference"`                               // erasure receipt or purge cutoff behind the action
+	CreatedAt      time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time `bson:"updatedAt" json:"updatedAt"`
+}
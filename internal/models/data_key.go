@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// DataKey is a key that encrypts payload fields, stored wrapped (encrypted)
+// with a master key. The newest data key encrypts new documents; older ones
+// are kept to decrypt the documents that name them.
+type DataKey struct {
+	ID          string    `bson:"_id" json:"id"`
+	MasterKeyID string    `bson:"masterKeyId" json:"masterKeyId"`
+	WrappedKey  []byte    `bson:"wrappedKey" json:"-"`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt"`
+	RewrappedAt time.Time `bson:"rewrappedAt,omitempty" json:"rewrappedAt,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Erasure run statuses
+const (
+	ErasureStatusInProgress = "in_progress"
+	ErasureStatusCompleted  = "completed"
+)
+
+// ErasureReceipt records the erasure of a user's activity logs. It is keyed
+// by the user's pseudonym so the receipt itself holds no user id. Counts
+// add up over every run for the user.
+type ErasureReceipt struct {
+	Subject     string             `bson:"_id" json:"subject"`
+	Mode        string             `bson:"mode" json:"mode"`
+	Status      string             `bson:"status" json:"status"`
+	Trigger     string             `bson:"trigger" json:"trigger"` // the event id or "cli"
+	FieldPaths  []string           `bson:"fieldPaths" json:"fieldPaths"`
+	Matched     int64              `bson:"matched" json:"matched"`
+	Anonymized  int64              `bson:"anonymized" json:"anonymized"`
+	Deleted     int64              `bson:"deleted" json:"deleted"`
+	Held        int64              `bson:"held" json:"held"` // left untouched by the last run because of a legal hold
+	Runs        int                `bson:"runs" json:"runs"`
+	Checkpoint  primitive.ObjectID `bson:"checkpoint,omitempty" json:"checkpoint,omitempty"` // last log handled by an unfinished run
+	StartedAt   time.Time          `bson:"startedAt" json:"startedAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	CompletedAt time.Time          `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Job run results
+const (
+	JobResultSuccess = "success"
+	JobResultFailed  = "failed"
+	JobResultTimeout = "timeout"
+)
+
+// JobStatus is the outcome of the last run of a scheduled job
+type JobStatus struct {
+	Name           string    `bson:"_id" json:"name"`
+	Schedule       string    `bson:"schedule" json:"schedule"`
+	LastStartedAt  time.Time `bson:"lastStartedAt" json:"lastStartedAt"`
+	LastFinishedAt time.Time `bson:"lastFinishedAt" json:"lastFinishedAt"`
+	LastDurationMs int64     `bson:"lastDurationMs" json:"lastDurationMs"`
+	LastResult     string    `bson:"lastResult" json:"lastResult"`
+	LastError      string    `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	LastInstance   string    `bson:"lastInstance" json:"lastInstance"`
+	LastTrigger    string    `bson:"lastTrigger" json:"lastTrigger"` // schedule or manual
+	RunCount       int64     `bson:"runCount" json:"runCount"`
+	FailureCount   int64     `bson:"failureCount" json:"failureCount"`
+}
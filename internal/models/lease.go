@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Lease grants one instance the right to run a singleton job until it
+// expires. Token increases every time the lease changes hands so writes made
+// by a previous holder can be fenced off.
+type Lease struct {
+	Name       string    `bson:"_id" json:"name"`
+	Owner      string    `bson:"owner" json:"owner"`
+	Token      int64     `bson:"token" json:"token"`
+	ExpiresAt  time.Time `bson:"expiresAt" json:"expiresAt"`
+	AcquiredAt time.Time `bson:"acquiredAt" json:"acquiredAt"`
+	RenewedAt  time.Time `bson:"renewedAt" json:"renewedAt"`
+}
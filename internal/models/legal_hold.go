@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LegalHold freezes the activity logs in its scope: retention and erasure
+// leave them alone until the hold is released. Every scope field that is
+// set must match; From and To bound the log timestamp, inclusive.
+type LegalHold struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceID string             `bson:"workspaceId,omitempty" json:"workspaceId,omitempty"`
+	UserID      string             `bson:"userId,omitempty" json:"userId,omitempty"`
+	Topic       string             `bson:"topic,omitempty" json:"topic,omitempty"`
+	From        time.Time          `bson:"from,omitempty" json:"from,omitempty"`
+	To          time.Time          `bson:"to,omitempty" json:"to,omitempty"`
+	Reason      string             `bson:"reason" json:"reason"`
+	CreatedBy   string             `bson:"createdBy" json:"createdBy"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	ReleasedBy  string             `bson:"releasedBy,omitempty" json:"releasedBy,omitempty"`
+	ReleasedAt  time.Time          `bson:"releasedAt,omitempty" json:"releasedAt,omitempty"`
+}
+
+// Active reports whether the hold has not been released
+func (h *LegalHold) Active() bool {
+	return h.ReleasedAt.IsZero()
+}
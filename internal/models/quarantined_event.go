@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Source violation kinds
+const (
+	ViolationRoutingKey  = "routing_key"  // the routing key is not the event topic
+	ViolationUser        = "user"         // the AMQP user-id may not publish as the source service
+	ViolationTopicSource = "topic_source" // the source service may not publish the topic
+)
+
+// SourceViolation is one reason an event's claimed origin was not trusted
+type SourceViolation struct {
+	Kind   string `bson:"kind" json:"kind"`
+	Detail string `bson:"detail" json:"detail"`
+}
+
+// QuarantinedEvent is an event that failed source verification. It is kept
+// for review instead of being stored as an activity log; the payload is
+// redacted and encrypted like an activity log's. There is one per event and
+// handler, however often the event is delivered.
+type QuarantinedEvent struct {
+	ID            primitive.ObjectID     `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID       string                 `bson:"eventId" json:"eventId"`
+	Topic         string                 `bson:"topic" json:"topic"`
+	SourceService string                 `bson:"sourceService" json:"sourceService"`
+	Timestamp     string                 `bson:"timestamp" json:"timestamp"`
+	Payload       map[string]interface{} `bson:"payload,omitempty" json:"payload,omitempty"`
+	Encryption    *EncryptionInfo        `bson:"encryption,omitempty" json:"encryption,omitempty"`
+	Handler       string                 `bson:"handler" json:"handler"` // the handler that rejected the event
+	RoutingKey    string                 `bson:"routingKey" json:"routingKey"`
+	UserID        string                 `bson:"userId,omitempty" json:"userId,omitempty"` // AMQP user-id of the publisher
+	Violations    []SourceViolation      `bson:"violations" json:"violations"`
+	QuarantinedAt time.Time              `bson:"quarantinedAt" json:"quarantinedAt"`
+}
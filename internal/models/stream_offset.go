@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// StreamOffset is the last offset a stream consumer has finished processing
+type StreamOffset struct {
+	Consumer  string    `bson:"_id" json:"consumer"`
+	Offset    int64     `bson:"offset" json:"offset"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type activityLogErasureRepository struct {
+	collection *mongo.Collection
+}
+
+func NewActivityLogErasureRepository() ActivityLogErasureRepository {
+	cfg := global.Config.MongoDB
+	collection := global.MongoDB.Collection(cfg.ActivityLogCollection)
+	return &activityLogErasureRepository{
+		collection: collection,
+	}
+}
+
+func (r *activityLogErasureRepository) FindReferencing(ctx context.Context, userID string, paths []string, holds HoldScope, afterID primitive.ObjectID, limit int) ([]models.ActivityLog, error) {
+	filter := withScope(referencing(userID, paths), holds.notHeld())
+	if !afterID.IsZero() {
+		filter = bson.M{"$and": bson.A{filter, bson.M{"_id": bson.M{"$gt": afterID}}}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	var logs []models.ActivityLog
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return logs, nil
+}
+
+func (r *activityLogErasureRepository) CountHeld(ctx context.Context, userID string, paths []string, holds HoldScope) (int64, error) {
+	if holds.Empty() {
+		return 0, nil
+	}
+
+	held, err := r.collection.CountDocuments(ctx, withScope(referencing(userID, paths), holds.held()))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return held, nil
+}
+
+// referencing matches logs holding userID at any of the payload paths, as
+// subject, or as the value of an extension or a kept header. A dotted path
+// also matches values inside arrays along the way.
+func referencing(userID string, paths []string) bson.M {
+	conditions := make(bson.A, 0, len(paths)+3)
+	for _, path := range paths {
+		conditions = append(conditions, bson.M{"payload." + path: userID})
+	}
+	conditions = append(conditions,
+		bson.M{"subject": userID},
+		bson.M{"$expr": valueOf(userID, "$extensions")},
+		bson.M{"$expr": valueOf(userID, "$metadata.headers")},
+	)
+	return bson.M{"$or": conditions}
+}
+
+// valueOf is an aggregation expression that is true when userID is one of
+// the values of the document at field
+func valueOf(userID, field string) bson.M {
+	return bson.M{"$in": bson.A{userID, bson.M{"$map": bson.M{
+		"input": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{field, bson.M{}}}},
+		"in":    "$$this.v",
+	}}}}
+}
+
+func (r *activityLogErasureRepository) ReplaceUserFields(ctx context.Context, logs []models.ActivityLog) (int64, error) {
+	if len(logs) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(logs))
+	for _, log := range logs {
+		set := bson.M{"payload": log.Payload}
+		if log.Subject != "" {
+			set["subject"] = log.Subject
+		}
+		if log.Extensions != nil {
+			set["extensions"] = log.Extensions
+		}
+		if log.Metadata != nil {
+			set["metadata"] = log.Metadata
+		}
+
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": log.ID}).
+			SetUpdate(bson.M{"$set": set}))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (r *activityLogErasureRepository) DeleteByIDs(ctx context.Context, ids []primitive.ObjectID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type activityLogQueryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewActivityLogQueryRepository() ActivityLogQueryRepository {
+	cfg := global.Config.MongoDB
+	collection := global.MongoDB.Collection(cfg.ActivityLogCollection)
+	return &activityLogQueryRepository{
+		collection: collection,
+	}
+}
+
+func (r *activityLogQueryRepository) UserTimeline(ctx context.Context, query TimelineQuery) ([]models.ActivityLog, error) {
+	fields := append(append([]string(nil), query.ActorFields...), query.SubjectFields...)
+
+	involved := make(bson.A, 0, len(fields))
+	for _, field := range fields {
+		involved = append(involved, bson.M{"payload." + field: query.UserID})
+	}
+
+	conditions := bson.A{bson.M{"$or": involved}}
+	if len(query.Topics) > 0 {
+		conditions = append(conditions, bson.M{"topic": bson.M{"$in": query.Topics}})
+	}
+	if !query.BeforeTimestamp.IsZero() {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"timestamp": bson.M{"$lt": query.BeforeTimestamp}},
+			bson.M{"timestamp": query.BeforeTimestamp, "_id": bson.M{"$lt": query.BeforeID}},
+		}})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(query.Limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	var logs []models.ActivityLog
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return logs, nil
+}
+
+func (r *activityLogQueryRepository) FindByEventID(ctx context.Context, eventID string) (*models.ActivityLog, error) {
+	var log models.ActivityLog
+	err := r.collection.FindOne(ctx, bson.M{"eventId": eventID}).Decode(&log)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return &log, nil
+}
+
+func (r *activityLogQueryRepository) ProcessedBetween(ctx context.Context, from, to time.Time, fn func(log *models.ActivityLog) error) error {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"processedAt": bson.M{"$gte": from, "$lt": to}},
+		options.Find().SetSort(bson.M{"eventId": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var log models.ActivityLog
+		if err := cursor.Decode(&log); err != nil {
+			return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+		}
+		if err := fn(&log); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+	return nil
+}
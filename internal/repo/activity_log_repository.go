@@ -15,13 +15,21 @@ import (
 
 type activityLogRepository struct {
 	collection *mongo.Collection
+	chain      *hashChain // nil unless audit.hash_chain is enabled
 }
 
 func NewActivityLogRepository() ActivityLogRepository {
 	cfg := global.Config.MongoDB
 	collection := global.MongoDB.Collection(cfg.ActivityLogCollection)
+
+	var chain *hashChain
+	if audit := global.Config.Audit; audit.HashChain {
+		chain = newHashChain(collection, global.MongoDB.Collection(common.ChainHeadCollection), audit.ChainPartitionKey)
+	}
+
 	return &activityLogRepository{
 		collection: collection,
+		chain:      chain,
 	}
 }
 
@@ -29,9 +37,15 @@ func (r *activityLogRepository) Create(ctx context.Context, log *models.Activity
 	log.ProcessedAt = time.Now()
 	log.Version = 1
 
-	result, err := r.collection.InsertOne(ctx, log)
+	var result *mongo.InsertOneResult
+	var err error
+	if r.chain != nil {
+		result, err = r.chain.insert(ctx, log)
+	} else {
+		result, err = r.collection.InsertOne(ctx, log)
+	}
 	if err != nil {
-		return fmt.Errorf("%w: %v", common.ErrMongoInsert, err)
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
 	}
 
 	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
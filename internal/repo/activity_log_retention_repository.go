@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const retentionBatchSize = 1000
+
+type activityLogRetentionRepository struct {
+	collection *mongo.Collection
+	tombstones *mongo.Collection
+}
+
+func NewActivityLogRetentionRepository() ActivityLogRetentionRepository {
+	cfg := global.Config.MongoDB
+	collection := global.MongoDB.Collection(cfg.ActivityLogCollection)
+	return &activityLogRetentionRepository{
+		collection: collection,
+		tombstones: tombstoneCollection(),
+	}
+}
+
+func (r *activityLogRetentionRepository) DeleteBefore(ctx context.Context, before time.Time, holds HoldScope) (int64, int64, error) {
+	expired := bson.M{"processedAt": bson.M{"$lt": before}}
+
+	var held int64
+	if !holds.Empty() {
+		var err error
+		held, err = r.collection.CountDocuments(ctx, withScope(expired, holds.held()))
+		if err != nil {
+			return 0, 0, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+		}
+	}
+
+	if !TombstonesEnabled() {
+		result, err := r.collection.DeleteMany(ctx, withScope(expired, holds.notHeld()))
+		if err != nil {
+			return 0, held, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+		}
+		return result.DeletedCount, held, nil
+	}
+
+	// Each purged log leaves a tombstone, so the chain still verifies
+	// through it. Deleting a batch moves the query on to the next one.
+	reference := "purge:" + before.UTC().Format(time.RFC3339)
+	var deleted int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, held, err
+		}
+
+		cursor, err := r.collection.Find(ctx, withScope(expired, holds.notHeld()),
+			options.Find().SetSort(bson.M{"_id": 1}).SetLimit(retentionBatchSize))
+		if err != nil {
+			return deleted, held, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+		}
+		var logs []models.ActivityLog
+		if err := cursor.All(ctx, &logs); err != nil {
+			return deleted, held, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+		}
+		if len(logs) == 0 {
+			return deleted, held, nil
+		}
+
+		ids := make([]primitive.ObjectID, 0, len(logs))
+		tombstones := make([]models.ChainTombstone, 0, len(logs))
+		for i := range logs {
+			contentHash, err := ChainContentHash(&logs[i])
+			if err != nil {
+				return deleted, held, err
+			}
+			ids = append(ids, logs[i].ID)
+			tombstones = append(tombstones, NewChainTombstone(&logs[i], contentHash, models.TombstonePurged, reference))
+		}
+
+		if err := recordTombstones(ctx, r.tombstones, tombstones); err != nil {
+			return deleted, held, err
+		}
+		result, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return deleted, held, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+		}
+		deleted += result.DeletedCount
+	}
+}
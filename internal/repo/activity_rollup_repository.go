@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type activityRollupRepository struct {
+	collection     *mongo.Collection
+	logsCollection *mongo.Collection
+}
+
+func NewActivityRollupRepository() ActivityRollupRepository {
+	cfg := global.Config.MongoDB
+	return &activityRollupRepository{
+		collection:     global.MongoDB.Collection(common.RollupCollection),
+		logsCollection: global.MongoDB.Collection(cfg.ActivityLogCollection),
+	}
+}
+
+// RollupDay returns the UTC day an activity log is counted in
+func RollupDay(log *models.ActivityLog) time.Time {
+	return log.Timestamp.UTC().Truncate(24 * time.Hour)
+}
+
+// RollupWorkspaceID returns the workspace an activity log is counted under
+func RollupWorkspaceID(log *models.ActivityLog) string {
+	workspaceID, _ := log.Payload["workspaceId"].(string)
+	return workspaceID
+}
+
+func (r *activityRollupRepository) Increment(ctx context.Context, log *models.ActivityLog) error {
+	filter := bson.M{
+		"day":           RollupDay(log),
+		"workspaceId":   RollupWorkspaceID(log),
+		"topic":         log.Topic,
+		"sourceService": log.SourceService,
+	}
+	update := bson.M{
+		"$inc": bson.M{"count": int64(1)},
+		"$set": bson.M{"updatedAt": time.Now()},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return nil
+}
+
+func (r *activityRollupRepository) Rebuild(ctx context.Context, from, to time.Time) (int64, error) {
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour)
+
+	// Rollups are replaced in place rather than deleted first, so an
+	// increment landing during the rebuild is not lost with them
+	rebuiltAt := time.Now()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"timestamp": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"day": bson.M{"$dateTrunc": bson.M{"date": "$timestamp", "unit": "day", "timezone": "UTC"}},
+				"workspaceId": bson.M{"$cond": bson.A{
+					bson.M{"$eq": bson.A{bson.M{"$type": "$payload.workspaceId"}, "string"}},
+					"$payload.workspaceId",
+					"",
+				}},
+				"topic":         "$topic",
+				"sourceService": "$sourceService",
+			},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":           0,
+			"day":           "$_id.day",
+			"workspaceId":   "$_id.workspaceId",
+			"topic":         "$_id.topic",
+			"sourceService": "$_id.sourceService",
+			"count":         bson.M{"$toLong": "$count"},
+			"updatedAt":     rebuiltAt,
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":           common.RollupCollection,
+			"on":             bson.A{"day", "workspaceId", "topic", "sourceService"},
+			"whenMatched":    "replace",
+			"whenNotMatched": "insert",
+		}}},
+	}
+
+	cursor, err := r.logsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+	cursor.Close(ctx)
+
+	// What the rebuild did not write has no logs anymore; rollups
+	// incremented meanwhile are newer than the rebuild and kept
+	_, err = r.collection.DeleteMany(ctx, bson.M{
+		"day":       bson.M{"$gte": from, "$lt": to},
+		"updatedAt": bson.M{"$lt": rebuiltAt},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	written, err := r.collection.CountDocuments(ctx, bson.M{"day": bson.M{"$gte": from, "$lt": to}})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return written, nil
+}
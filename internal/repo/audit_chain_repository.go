@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auditChainRepository struct {
+	logs       *mongo.Collection
+	heads      *mongo.Collection
+	tombstones *mongo.Collection
+}
+
+func NewAuditChainRepository() AuditChainRepository {
+	cfg := global.Config.MongoDB
+	return &auditChainRepository{
+		logs:       global.MongoDB.Collection(cfg.ActivityLogCollection),
+		heads:      global.MongoDB.Collection(common.ChainHeadCollection),
+		tombstones: tombstoneCollection(),
+	}
+}
+
+func (r *auditChainRepository) Partitions(ctx context.Context) ([]string, error) {
+	partitions := make(map[string]bool)
+
+	values, err := r.logs.Distinct(ctx, "chain.partition", bson.M{"chain.seq": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+	for _, value := range values {
+		if name, ok := value.(string); ok {
+			partitions[name] = true
+		}
+	}
+
+	// A partition whose logs were all deleted only has its head left
+	heads, err := r.heads.Distinct(ctx, "_id", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+	for _, value := range heads {
+		if name, ok := value.(string); ok {
+			partitions[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(partitions))
+	for name := range partitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (r *auditChainRepository) Walk(ctx context.Context, partition string, fn func(log *models.ActivityLog) error) error {
+	cursor, err := r.logs.Find(ctx,
+		bson.M{"chain.partition": partition},
+		options.Find().SetSort(bson.D{{Key: "chain.partition", Value: 1}, {Key: "chain.seq", Value: 1}}),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var log models.ActivityLog
+		if err := cursor.Decode(&log); err != nil {
+			return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+		}
+		if err := fn(&log); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+	return nil
+}
+
+func (r *auditChainRepository) Head(ctx context.Context, partition string) (*models.ChainHead, error) {
+	var head models.ChainHead
+	err := r.heads.FindOne(ctx, bson.M{"_id": partition}).Decode(&head)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return &head, nil
+}
+
+func (r *auditChainRepository) RecordTombstones(ctx context.Context, tombstones []models.ChainTombstone) error {
+	return recordTombstones(ctx, r.tombstones, tombstones)
+}
+
+func (r *auditChainRepository) WalkTombstones(ctx context.Context, partition string, fromSeq, toSeq int64, fn func(tombstone *models.ChainTombstone) error) error {
+	cursor, err := r.tombstones.Find(ctx,
+		bson.M{"partition": partition, "seq": bson.M{"$gte": fromSeq, "$lte": toSeq}},
+		options.Find().SetSort(bson.D{{Key: "partition", Value: 1}, {Key: "seq", Value: 1}}),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var tombstone models.ChainTombstone
+		if err := cursor.Decode(&tombstone); err != nil {
+			return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+		}
+		if err := fn(&tombstone); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+	return nil
+}
+
+func (r *auditChainRepository) Tombstone(ctx context.Context, eventID string) (*models.ChainTombstone, error) {
+	var tombstone models.ChainTombstone
+	err := r.tombstones.FindOne(ctx, bson.M{"eventId": eventID}).Decode(&tombstone)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return &tombstone, nil
+}
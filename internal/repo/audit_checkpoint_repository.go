@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// checkpointLeafBatch bounds the leaves written per insert
+const checkpointLeafBatch = 1000
+
+type auditCheckpointRepository struct {
+	collection *mongo.Collection
+	leaves     *mongo.Collection
+}
+
+func NewAuditCheckpointRepository() AuditCheckpointRepository {
+	return &auditCheckpointRepository{
+		collection: global.MongoDB.Collection(common.CheckpointCollection),
+		leaves:     global.MongoDB.Collection(common.CheckpointLeafCollection),
+	}
+}
+
+func (r *auditCheckpointRepository) Create(ctx context.Context, checkpoint *models.AuditCheckpoint) error {
+	if _, err := r.collection.InsertOne(ctx, checkpoint); err != nil {
+		// The unique prevId index lets only one checkpoint follow another
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s: %w", common.ErrCheckpointFork, checkpoint.PrevID, err)
+		}
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return nil
+}
+
+func (r *auditCheckpointRepository) Get(ctx context.Context, id string) (*models.AuditCheckpoint, error) {
+	return r.findOne(ctx, bson.M{"_id": id}, options.FindOne())
+}
+
+func (r *auditCheckpointRepository) Latest(ctx context.Context) (*models.AuditCheckpoint, error) {
+	return r.findOne(ctx, bson.M{}, options.FindOne().SetSort(bson.M{"to": -1}))
+}
+
+func (r *auditCheckpointRepository) findOne(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*models.AuditCheckpoint, error) {
+	var checkpoint models.AuditCheckpoint
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&checkpoint)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return &checkpoint, nil
+}
+
+func (r *auditCheckpointRepository) CreateLeaves(ctx context.Context, leaves []models.AuditCheckpointLeaf) error {
+	for start := 0; start < len(leaves); start += checkpointLeafBatch {
+		end := min(start+checkpointLeafBatch, len(leaves))
+
+		documents := make([]interface{}, 0, end-start)
+		for i := start; i < end; i++ {
+			documents = append(documents, leaves[i])
+		}
+
+		if _, err := r.leaves.InsertMany(ctx, documents); err != nil {
+			return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *auditCheckpointRepository) DeleteLeaves(ctx context.Context, checkpointID string) error {
+	if _, err := r.leaves.DeleteMany(ctx, bson.M{"checkpointId": checkpointID}); err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return nil
+}
+
+func (r *auditCheckpointRepository) Leaves(ctx context.Context, checkpointID string) ([]models.AuditCheckpointLeaf, error) {
+	cursor, err := r.leaves.Find(ctx,
+		bson.M{"checkpointId": checkpointID},
+		options.Find().SetSort(bson.M{"index": 1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	var leaves []models.AuditCheckpointLeaf
+	if err := cursor.All(ctx, &leaves); err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return leaves, nil
+}
+
+func (r *auditCheckpointRepository) FindLeaf(ctx context.Context, eventID, checkpointID string) (*models.AuditCheckpointLeaf, error) {
+	filter := bson.M{"eventId": eventID}
+	if checkpointID != "" {
+		filter["checkpointId"] = checkpointID
+	}
+
+	var leaf models.AuditCheckpointLeaf
+	err := r.leaves.FindOne(ctx, filter, options.FindOne().SetSort(bson.M{"_id": -1})).Decode(&leaf)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return &leaf, nil
+}
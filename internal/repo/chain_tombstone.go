@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TombstonesEnabled reports whether rewriting or deleting logs leaves chain
+// tombstones: only when the hash chain or signed checkpoints can tell
+// the change apart from tampering
+func TombstonesEnabled() bool {
+	audit := global.Config.Audit
+	return audit.HashChain || audit.CheckpointSigningKey != ""
+}
+
+// NewChainTombstone records action on log, as read before the action.
+// contentHash is the hash of that content; a chained log keeps the hash it
+// was linked with instead, which differs once the log was anonymized.
+func NewChainTombstone(log *models.ActivityLog, contentHash, action, reference string) models.ChainTombstone {
+	tombstone := models.ChainTombstone{
+		EventID:     log.EventID,
+		ContentHash: contentHash,
+		Action:      action,
+		Reference:   reference,
+	}
+	if link := log.Chain; link != nil {
+		tombstone.Partition = link.Partition
+		tombstone.Seq = link.Seq
+		tombstone.ContentHash = link.ContentHash
+		tombstone.PrevHash = link.PrevHash
+		tombstone.Hash = link.Hash
+	}
+	return tombstone
+}
+
+// recordTombstones stores tombstones before their action is carried out, so
+// a run that stops in between leaves a tombstone for a log that is still
+// intact rather than a deleted log without one
+func recordTombstones(ctx context.Context, collection *mongo.Collection, tombstones []models.ChainTombstone) error {
+	if len(tombstones) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(tombstones))
+	for _, tombstone := range tombstones {
+		// The first action keeps the link and the stored content hash;
+		// later ones only update what happened
+		insert := bson.M{
+			"contentHash": tombstone.ContentHash,
+			"createdAt":   now,
+		}
+		if tombstone.Partition != "" {
+			insert["partition"] = tombstone.Partition
+			insert["seq"] = tombstone.Seq
+			insert["prevHash"] = tombstone.PrevHash
+			insert["hash"] = tombstone.Hash
+		}
+		set := bson.M{
+			"action":    tombstone.Action,
+			"reference": tombstone.Reference,
+			"updatedAt": now,
+		}
+		if tombstone.NewContentHash != "" {
+			set["newContentHash"] = tombstone.NewContentHash
+		}
+
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"eventId": tombstone.EventID}).
+			SetUpdate(bson.M{"$setOnInsert": insert, "$set": set}).
+			SetUpsert(true))
+	}
+
+	if _, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+	return nil
+}
+
+func tombstoneCollection() *mongo.Collection {
+	return global.MongoDB.Collection(common.ChainTombstoneCollection)
+}
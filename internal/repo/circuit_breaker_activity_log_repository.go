@@ -0,0 +1,33 @@
+package repo
+
+import (
+	"context"
+
+	"event_service/internal/breaker"
+	"event_service/internal/models"
+)
+
+// circuitBreakerActivityLogRepository stops sending writes to MongoDB while
+// the breaker is open. Only connectivity failures count towards opening it;
+// rejected documents (e.g. duplicate keys) are not a sign of a sick server.
+type circuitBreakerActivityLogRepository struct {
+	next    ActivityLogRepository
+	breaker *breaker.Breaker
+}
+
+func NewCircuitBreakerActivityLogRepository(next ActivityLogRepository, b *breaker.Breaker) ActivityLogRepository {
+	return &circuitBreakerActivityLogRepository{
+		next:    next,
+		breaker: b,
+	}
+}
+
+func (r *circuitBreakerActivityLogRepository) Create(ctx context.Context, log *models.ActivityLog) error {
+	if err := r.breaker.Allow(); err != nil {
+		return err
+	}
+
+	err := r.next.Create(ctx, log)
+	r.breaker.Record(IsConnectivityError(err))
+	return err
+}
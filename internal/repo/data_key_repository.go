@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type dataKeyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDataKeyRepository() DataKeyRepository {
+	collection := global.MongoDB.Collection(common.DataKeyCollection)
+	return &dataKeyRepository{
+		collection: collection,
+	}
+}
+
+func (r *dataKeyRepository) Create(ctx context.Context, key *models.DataKey) error {
+	if _, err := r.collection.InsertOne(ctx, key); err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return nil
+}
+
+func (r *dataKeyRepository) Get(ctx context.Context, id string) (*models.DataKey, error) {
+	return r.findOne(ctx, bson.M{"_id": id}, options.FindOne())
+}
+
+func (r *dataKeyRepository) Latest(ctx context.Context) (*models.DataKey, error) {
+	return r.findOne(ctx, bson.M{}, options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}))
+}
+
+func (r *dataKeyRepository) findOne(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*models.DataKey, error) {
+	var key models.DataKey
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return &key, nil
+}
+
+func (r *dataKeyRepository) List(ctx context.Context) ([]models.DataKey, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	var keys []models.DataKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return keys, nil
+}
+
+func (r *dataKeyRepository) Rewrap(ctx context.Context, id, masterKeyID string, wrapped []byte) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"masterKeyId": masterKeyID,
+			"wrappedKey":  wrapped,
+			"rewrappedAt": time.Now(),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return nil
+}
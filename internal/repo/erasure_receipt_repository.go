@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type erasureReceiptRepository struct {
+	collection *mongo.Collection
+}
+
+func NewErasureReceiptRepository() ErasureReceiptRepository {
+	collection := global.MongoDB.Collection(common.ErasureCollection)
+	return &erasureReceiptRepository{
+		collection: collection,
+	}
+}
+
+func (r *erasureReceiptRepository) Get(ctx context.Context, subject string) (*models.ErasureReceipt, error) {
+	var receipt models.ErasureReceipt
+	err := r.collection.FindOne(ctx, bson.M{"_id": subject}).Decode(&receipt)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return &receipt, nil
+}
+
+func (r *erasureReceiptRepository) Save(ctx context.Context, receipt *models.ErasureReceipt) error {
+	_, err := r.collection.ReplaceOne(ctx,
+		bson.M{"_id": receipt.Subject},
+		receipt,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package repo
+
+import (
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// eventIDIndexName is the unique index that stores each event once
+const eventIDIndexName = "eventId_idx"
+
+// IsConnectivityError reports whether err means MongoDB could not be reached,
+// as opposed to the write itself being rejected
+func IsConnectivityError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return mongo.IsNetworkError(err) ||
+		mongo.IsTimeout(err) ||
+		errors.Is(err, mongo.ErrClientDisconnected)
+}
+
+// IsDuplicateEvent reports whether err means an activity log for the same
+// event is already stored, e.g. because a delivery was redelivered after its
+// insert committed
+func IsDuplicateEvent(err error) bool {
+	return err != nil && mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), eventIDIndexName)
+}
@@ -0,0 +1,301 @@
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// DefaultChainPartition holds the logs without a partition key
+	DefaultChainPartition = "_default"
+
+	chainIndexName    = "chain_partition_seq_idx"
+	chainInsertTrials = 20
+)
+
+// hashChain links every inserted log to the previous log of its partition.
+// The unique (partition, seq) index makes concurrent writers of the same
+// partition retry instead of forking the chain.
+type hashChain struct {
+	logs         *mongo.Collection
+	heads        *mongo.Collection
+	partitionKey []string
+}
+
+func newHashChain(logs, heads *mongo.Collection, partitionKey string) *hashChain {
+	c := &hashChain{logs: logs, heads: heads}
+	if partitionKey != "" {
+		c.partitionKey = strings.Split(partitionKey, ".")
+	}
+	return c
+}
+
+func (c *hashChain) partition(log *models.ActivityLog) string {
+	if len(c.partitionKey) == 0 {
+		return DefaultChainPartition
+	}
+
+	var current interface{} = log.Payload
+	for _, segment := range c.partitionKey {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return DefaultChainPartition
+		}
+		current = m[segment]
+	}
+
+	if value, ok := current.(string); ok && value != "" {
+		return value
+	}
+	return DefaultChainPartition
+}
+
+// insert links log to the chain of its partition and inserts it
+func (c *hashChain) insert(ctx context.Context, log *models.ActivityLog) (*mongo.InsertOneResult, error) {
+	contentHash, err := ChainContentHash(log)
+	if err != nil {
+		return nil, err
+	}
+	partition := c.partition(log)
+
+	for trial := 0; trial < chainInsertTrials; trial++ {
+		seq, prevHash, err := c.tip(ctx, partition)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Chain = &models.ChainLink{
+			Partition:   partition,
+			Seq:         seq + 1,
+			ContentHash: contentHash,
+			PrevHash:    prevHash,
+			Hash:        ChainLinkHash(partition, seq+1, contentHash, prevHash),
+		}
+
+		result, err := c.logs.InsertOne(ctx, log)
+		if isChainConflict(err) {
+			// Another writer took this sequence number first
+			continue
+		}
+		if err != nil {
+			log.Chain = nil
+			return nil, err
+		}
+
+		c.advanceHead(ctx, log.Chain)
+		return result, nil
+	}
+
+	log.Chain = nil
+	return nil, fmt.Errorf("%w: too much contention on hash chain %s", common.ErrMongoInsert, partition)
+}
+
+// tip returns the sequence number and hash the next log links to: the
+// newest log of the partition, or the head if newer logs were deleted
+func (c *hashChain) tip(ctx context.Context, partition string) (int64, string, error) {
+	var latest models.ActivityLog
+	err := c.logs.FindOne(ctx,
+		bson.M{"chain.partition": partition},
+		options.FindOne().
+			SetSort(bson.D{{Key: "chain.partition", Value: 1}, {Key: "chain.seq", Value: -1}}).
+			SetProjection(bson.M{"chain": 1}),
+	).Decode(&latest)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, "", err
+	}
+
+	var seq int64
+	var hash string
+	if latest.Chain != nil {
+		seq, hash = latest.Chain.Seq, latest.Chain.Hash
+	}
+
+	var head models.ChainHead
+	err = c.heads.FindOne(ctx, bson.M{"_id": partition}).Decode(&head)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, "", err
+	}
+	if head.Seq > seq {
+		seq, hash = head.Seq, head.Hash
+	}
+
+	return seq, hash, nil
+}
+
+// advanceHead records link as the partition head unless a newer one is
+// already recorded. A failure only delays the head; the log is stored.
+func (c *hashChain) advanceHead(ctx context.Context, link *models.ChainLink) {
+	_, err := c.heads.UpdateOne(ctx,
+		bson.M{"_id": link.Partition, "seq": bson.M{"$lt": link.Seq}},
+		bson.M{"$set": bson.M{"seq": link.Seq, "hash": link.Hash, "updatedAt": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		fmt.Printf("Error advancing hash chain head %s: %v\n", link.Partition, err)
+	}
+}
+
+func isChainConflict(err error) bool {
+	return err != nil && mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), chainIndexName)
+}
+
+// ChainContentHash hashes the stored content of a log: everything but its
+// id and chain link, in a canonical form that survives a MongoDB round trip
+func ChainContentHash(log *models.ActivityLog) (string, error) {
+	content := map[string]interface{}{
+		"eventId":       log.EventID,
+		"topic":         log.Topic,
+		"sourceService": log.SourceService,
+		"timestamp":     log.Timestamp.UnixMilli(),
+		"processedAt":   log.ProcessedAt.UnixMilli(),
+		"version":       log.Version,
+		"payload":       canonicalValue(log.Payload),
+	}
+	// Only when set, so logs stored before these fields existed keep their
+	// hash
+	if log.Subject != "" {
+		content["subject"] = log.Subject
+	}
+	if len(log.Extensions) > 0 {
+		content["extensions"] = canonicalValue(log.Extensions)
+	}
+	if len(log.Metadata) > 0 {
+		content["metadata"] = canonicalValue(log.Metadata)
+	}
+	if log.SchemaVersion != 0 {
+		content["schemaVersion"] = log.SchemaVersion
+	}
+	if log.UpcastFrom != 0 {
+		content["upcastFrom"] = log.UpcastFrom
+	}
+	if len(log.Redactions) > 0 {
+		content["redactions"] = log.Redactions
+	}
+	if log.Encryption != nil {
+		content["encryption"] = map[string]interface{}{
+			"keyId": log.Encryption.KeyID,
+			"paths": log.Encryption.Paths,
+		}
+	}
+
+	// encoding/json writes map keys sorted
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash activity log %s: %v", log.EventID, err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ChainLinkHash is the hash of a chain link
+func ChainLinkHash(partition string, seq int64, contentHash, prevHash string) string {
+	sum := sha256.Sum256([]byte(partition + "\n" + strconv.FormatInt(seq, 10) + "\n" + contentHash + "\n" + prevHash))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalValue converts a payload to one form whether it was just
+// decoded (JSON, Protobuf, MessagePack) or read back from MongoDB:
+//   - every integer kind becomes its exact decimal, since BSON widens the
+//     small kinds MessagePack produces and a float64 would round those above
+//     2^53; below that it is the digits a float64 was written with before
+//   - float32 becomes the float64 BSON stores it as
+//   - NaN and ±Inf, which JSON can't hold, become a "$float" key
+//   - binary data becomes its base64 under a "$binary" key
+func canonicalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = canonicalValue(item)
+		}
+		return out
+	case primitive.M:
+		return canonicalValue(map[string]interface{}(v))
+	case primitive.D:
+		out := make(map[string]interface{}, len(v))
+		for _, element := range v {
+			out[element.Key] = canonicalValue(element.Value)
+		}
+		return out
+	case primitive.A:
+		return canonicalValue([]interface{}(v))
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = canonicalValue(item)
+		}
+		return out
+	case int:
+		return canonicalInt(int64(v))
+	case int8:
+		return canonicalInt(int64(v))
+	case int16:
+		return canonicalInt(int64(v))
+	case int32:
+		return canonicalInt(int64(v))
+	case int64:
+		return canonicalInt(v)
+	case uint:
+		return canonicalUint(uint64(v))
+	case uint8:
+		return canonicalUint(uint64(v))
+	case uint16:
+		return canonicalUint(uint64(v))
+	case uint32:
+		return canonicalUint(uint64(v))
+	case uint64:
+		return canonicalUint(v)
+	case float32:
+		return canonicalFloat(float64(v))
+	case float64:
+		return canonicalFloat(v)
+	case []byte:
+		return map[string]interface{}{"$binary": base64.StdEncoding.EncodeToString(v)}
+	case primitive.Binary:
+		return map[string]interface{}{"$binary": base64.StdEncoding.EncodeToString(v.Data)}
+	case primitive.DateTime:
+		return v.Time().UnixMilli()
+	case time.Time:
+		return v.UnixMilli()
+	default:
+		return v
+	}
+}
+
+func canonicalInt(v int64) json.Number {
+	return json.Number(strconv.FormatInt(v, 10))
+}
+
+func canonicalUint(v uint64) json.Number {
+	return json.Number(strconv.FormatUint(v, 10))
+}
+
+func canonicalFloat(v float64) interface{} {
+	switch {
+	case math.IsNaN(v):
+		return map[string]interface{}{"$float": "NaN"}
+	case math.IsInf(v, 1):
+		return map[string]interface{}{"$float": "+Inf"}
+	case math.IsInf(v, -1):
+		return map[string]interface{}{"$float": "-Inf"}
+	}
+	return v
+}
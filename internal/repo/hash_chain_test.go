@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"event_service/internal/envelope"
+	"event_service/internal/models"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// A log decoded from MessagePack must hash the same after a MongoDB round
+// trip, or audit verify reports untouched logs as modified
+func TestChainContentHashSurvivesMsgpackRoundTrip(t *testing.T) {
+	body, err := msgpack.Marshal(map[string]interface{}{
+		"eventId":       "evt-1",
+		"topic":         "user.updated.log",
+		"sourceService": "iam",
+		"timestamp":     "2026-10-18T10:00:00Z",
+		"subject":       "user-1",
+		"payload": map[string]interface{}{
+			"int8":    int8(-3),
+			"int16":   int16(-300),
+			"int32":   int32(-70000),
+			"int64":   int64(1) << 40,
+			"uint8":   uint8(200),
+			"uint16":  uint16(60000),
+			"uint32":  uint32(4000000000),
+			"uint64":  uint64(1) << 50,
+			"float32": float32(0.1),
+			"float64": 0.1,
+			// Above 2^53, where a float64 no longer tells neighbours apart
+			"int64Big":  int64(1)<<60 + 1,
+			"uint64Big": uint64(1)<<62 + 1,
+			"nan":       math.NaN(),
+			"nan32":     float32(math.NaN()),
+			"inf":       math.Inf(1),
+			"negInf":    math.Inf(-1),
+			"binary":    []byte{0x00, 0xff, 0x10},
+			"nested": map[string]interface{}{
+				"list": []interface{}{uint8(1), float32(2.5), "three"},
+			},
+		},
+		"extensions": map[string]interface{}{"sequence": uint16(7)},
+	})
+	if err != nil {
+		t.Fatalf("encode msgpack: %v", err)
+	}
+
+	event, err := envelope.Decode(&envelope.Message{Body: body, ContentType: "application/msgpack"})
+	if err != nil {
+		t.Fatalf("decode msgpack: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	written := &models.ActivityLog{
+		EventID:       event.EventID,
+		Topic:         event.Topic,
+		SourceService: event.SourceService,
+		Timestamp:     now,
+		ProcessedAt:   now,
+		Subject:       event.Subject,
+		Payload:       event.Payload,
+		Extensions:    event.Extensions,
+	}
+
+	before, err := ChainContentHash(written)
+	if err != nil {
+		t.Fatalf("hash before round trip: %v", err)
+	}
+
+	data, err := bson.Marshal(written)
+	if err != nil {
+		t.Fatalf("encode bson: %v", err)
+	}
+	var read models.ActivityLog
+	if err := bson.Unmarshal(data, &read); err != nil {
+		t.Fatalf("decode bson: %v", err)
+	}
+
+	after, err := ChainContentHash(&read)
+	if err != nil {
+		t.Fatalf("hash after round trip: %v", err)
+	}
+
+	if before != after {
+		t.Errorf("content hash changed over a MongoDB round trip: %s before, %s after", before, after)
+	}
+}
+
+// Integers a float64 can't represent exactly must still hash apart
+func TestChainContentHashKeepsLargeIntegersExact(t *testing.T) {
+	hash := func(value int64) string {
+		t.Helper()
+		sum, err := ChainContentHash(&models.ActivityLog{
+			EventID: "evt-1",
+			Payload: map[string]interface{}{"amount": value},
+		})
+		if err != nil {
+			t.Fatalf("hash %d: %v", value, err)
+		}
+		return sum
+	}
+
+	if hash(1<<53) == hash(1<<53+1) {
+		t.Errorf("2^53 and 2^53+1 hash the same")
+	}
+}
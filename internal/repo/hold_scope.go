@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// HoldScope matches the activity logs covered by any of a set of legal
+// holds. Destructive queries exclude it and count what it kept.
+type HoldScope struct {
+	Holds     []models.LegalHold
+	UserPaths []string // payload paths a hold's user may appear at, besides subject, extensions and headers
+}
+
+// Empty reports whether no hold is in force
+func (s HoldScope) Empty() bool {
+	return len(s.Holds) == 0
+}
+
+// held matches logs under at least one hold
+func (s HoldScope) held() bson.M {
+	return bson.M{"$or": s.conditions()}
+}
+
+// notHeld matches logs under none of the holds
+func (s HoldScope) notHeld() bson.M {
+	if s.Empty() {
+		return bson.M{}
+	}
+	return bson.M{"$nor": s.conditions()}
+}
+
+func (s HoldScope) conditions() bson.A {
+	conditions := make(bson.A, 0, len(s.Holds))
+	for _, hold := range s.Holds {
+		conditions = append(conditions, s.holdFilter(hold))
+	}
+	return conditions
+}
+
+func (s HoldScope) holdFilter(hold models.LegalHold) bson.M {
+	var and bson.A
+
+	if hold.WorkspaceID != "" {
+		and = append(and, bson.M{"payload.workspaceId": hold.WorkspaceID})
+	}
+	if hold.UserID != "" {
+		// The same clauses erasure selects the user's logs with, so a hold
+		// covers every log erasure would otherwise touch
+		and = append(and, referencing(hold.UserID, s.UserPaths))
+	}
+	if hold.Topic != "" {
+		and = append(and, bson.M{"topic": hold.Topic})
+	}
+
+	timestamp := bson.M{}
+	if !hold.From.IsZero() {
+		timestamp["$gte"] = hold.From
+	}
+	if !hold.To.IsZero() {
+		timestamp["$lte"] = hold.To
+	}
+	if len(timestamp) > 0 {
+		and = append(and, bson.M{"timestamp": timestamp})
+	}
+
+	if len(and) == 0 {
+		// An unscoped hold freezes everything
+		return bson.M{}
+	}
+	return bson.M{"$and": and}
+}
+
+// withScope combines filter with a hold condition
+func withScope(filter, scope bson.M) bson.M {
+	if len(scope) == 0 {
+		return filter
+	}
+	return bson.M{"$and": bson.A{filter, scope}}
+}
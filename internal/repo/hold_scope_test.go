@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var holdTestPaths = []string{"userId", "targetUserId", "members.userId"}
+
+// A user hold selects logs with the same clauses erasure does
+func TestUserHoldUsesErasureClauses(t *testing.T) {
+	scope := HoldScope{UserPaths: holdTestPaths}
+	got := scope.holdFilter(models.LegalHold{UserID: "user-1"})
+	want := bson.M{"$and": bson.A{referencing("user-1", holdTestPaths)}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("user hold filter %v, want %v", got, want)
+	}
+}
+
+// Erasure and a user hold must select the same logs, or erasure touches
+// logs the hold should have kept. Runs against the MongoDB at
+// MONGODB_TEST_URI.
+func TestUserHoldMatchesErasureSelection(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	database := client.Database("event_service_test_" + primitive.NewObjectID().Hex())
+	defer database.Drop(context.Background())
+	collection := database.Collection("activity_logs")
+
+	const userID = "user-1"
+	documents := []interface{}{
+		bson.M{"payload": bson.M{"userId": userID}},
+		bson.M{"payload": bson.M{"targetUserId": userID}},
+		bson.M{"payload": bson.M{"members": bson.A{bson.M{"userId": "user-2"}, bson.M{"userId": userID}}}},
+		bson.M{"payload": bson.M{}, "subject": userID},
+		bson.M{"payload": bson.M{}, "extensions": bson.M{"actor": userID}},
+		bson.M{"payload": bson.M{}, "metadata": bson.M{"headers": bson.M{"x-user-id": userID}}},
+		bson.M{"payload": bson.M{"userId": "user-2"}, "subject": "user-2"},
+		bson.M{"payload": bson.M{"note": userID}},
+		bson.M{"payload": bson.M{}, "metadata": bson.M{"eventId": userID}},
+	}
+	if _, err := collection.InsertMany(ctx, documents); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	scope := HoldScope{
+		Holds:     []models.LegalHold{{UserID: userID}},
+		UserPaths: holdTestPaths,
+	}
+
+	erased := matchingIDs(ctx, t, collection, referencing(userID, holdTestPaths))
+	held := matchingIDs(ctx, t, collection, scope.held())
+
+	if len(erased) != 6 {
+		t.Errorf("erasure selected %d logs, want 6", len(erased))
+	}
+	if !reflect.DeepEqual(erased, held) {
+		t.Errorf("erasure selected %v, user hold selected %v", erased, held)
+	}
+}
+
+func matchingIDs(ctx context.Context, t *testing.T, collection *mongo.Collection, filter bson.M) []string {
+	t.Helper()
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+
+	var logs []models.ActivityLog
+	if err := cursor.All(ctx, &logs); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	ids := make([]string, 0, len(logs))
+	for _, log := range logs {
+		ids = append(ids, log.ID.Hex())
+	}
+	sort.Strings(ids)
+	return ids
+}
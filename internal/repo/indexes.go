@@ -0,0 +1,184 @@
+package repo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ActivityLogIndexes returns the indexes the activity log collection is
+// expected to have
+func ActivityLogIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "topic", Value: 1},
+				{Key: "processedAt", Value: -1},
+			},
+			Options: options.Index().SetName("topic_processedAt_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "payload.userId", Value: 1},
+				{Key: "processedAt", Value: -1},
+			},
+			Options: options.Index().SetName("userId_processedAt_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "eventId", Value: 1},
+			},
+			Options: options.Index().SetName(eventIDIndexName).SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "sourceService", Value: 1},
+				{Key: "processedAt", Value: -1},
+			},
+			Options: options.Index().SetName("sourceService_processedAt_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "timestamp", Value: -1},
+			},
+			Options: options.Index().SetName("timestamp_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "processedAt", Value: -1},
+			},
+			Options: options.Index().SetName("processedAt_idx"),
+		},
+		// User timeline: one index per actor/subject field so each branch of
+		// the $or is an index scan in timestamp order
+		timelineIndex("payload.createdById", "timeline_createdById_idx"),
+		timelineIndex("payload.actorId", "timeline_actorId_idx"),
+		timelineIndex("payload.userId", "timeline_userId_idx"),
+		timelineIndex("payload.memberId", "timeline_memberId_idx"),
+		// Hash chain: one log per sequence number and partition. Logs stored
+		// before the chain was enabled have no link and are not indexed.
+		{
+			Keys: bson.D{
+				{Key: "chain.partition", Value: 1},
+				{Key: "chain.seq", Value: 1},
+			},
+			Options: options.Index().
+				SetName(chainIndexName).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"chain.seq": bson.M{"$exists": true}}),
+		},
+	}
+}
+
+func timelineIndex(field, name string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: field, Value: 1},
+			{Key: "timestamp", Value: -1},
+			{Key: "_id", Value: -1},
+		},
+		Options: options.Index().SetName(name),
+	}
+}
+
+// IndexName returns the name an index model is created with
+func IndexName(index mongo.IndexModel) string {
+	if index.Options != nil && index.Options.Name != nil {
+		return *index.Options.Name
+	}
+	return ""
+}
+
+// ActivityRollupIndexes returns the indexes of the rollup collection. The
+// unique index identifies a rollup for upserts and for $merge.
+func ActivityRollupIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "day", Value: 1},
+				{Key: "workspaceId", Value: 1},
+				{Key: "topic", Value: 1},
+				{Key: "sourceService", Value: 1},
+			},
+			Options: options.Index().SetName("day_workspace_topic_source_idx").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "day", Value: -1},
+			},
+			Options: options.Index().SetName("workspace_day_idx"),
+		},
+	}
+}
+
+// AuditCheckpointIndexes returns the indexes of the checkpoint collection.
+// prevId is unique so two leaders cannot both append to the same checkpoint
+// and fork the chain; the first checkpoint has no prevId and is left out.
+func AuditCheckpointIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "prevId", Value: 1}},
+			Options: options.Index().
+				SetName("prevId_idx").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"prevId": bson.M{"$exists": true}}),
+		},
+	}
+}
+
+// AuditCheckpointLeafIndexes returns the indexes of the checkpoint leaf
+// collection: leaves in order per checkpoint, and the lookup by event.
+func AuditCheckpointLeafIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "checkpointId", Value: 1},
+				{Key: "index", Value: 1},
+			},
+			Options: options.Index().SetName("checkpoint_index_idx").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "eventId", Value: 1}},
+			Options: options.Index().SetName("leaf_eventId_idx"),
+		},
+	}
+}
+
+// ChainTombstoneIndexes returns the indexes of the chain tombstone
+// collection: one tombstone per event, and the walk of a partition's
+// tombstones in sequence order. Tombstones of unchained logs have no seq.
+func ChainTombstoneIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "eventId", Value: 1}},
+			Options: options.Index().SetName("tombstone_eventId_idx").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "partition", Value: 1},
+				{Key: "seq", Value: 1},
+			},
+			Options: options.Index().
+				SetName("tombstone_partition_seq_idx").
+				SetPartialFilterExpression(bson.M{"seq": bson.M{"$exists": true}}),
+		},
+	}
+}
+
+// QuarantineIndexes returns the indexes of the quarantine collection: an
+// event is quarantined once per handler. Events without an id are left out.
+func QuarantineIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "eventId", Value: 1},
+				{Key: "handler", Value: 1},
+			},
+			Options: options.Index().
+				SetName("eventId_handler_idx").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"eventId": bson.M{"$gt": ""}}),
+		},
+	}
+}
@@ -3,8 +3,152 @@ package repo
 import (
 	"context"
 	"event_service/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ActivityLogRepository interface {
 	Create(ctx context.Context, log *models.ActivityLog) error
 }
+
+type StreamOffsetRepository interface {
+	// Get returns the stored offset of consumer and whether one exists
+	Get(ctx context.Context, consumer string) (int64, bool, error)
+	Save(ctx context.Context, consumer string, offset int64) error
+}
+
+type LeaseRepository interface {
+	// Acquire takes the lease if it is free or expired and returns it;
+	// acquired is false while another owner holds it
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (lease *models.Lease, acquired bool, err error)
+	// Renew extends the lease and reports whether owner still holds it with token
+	Renew(ctx context.Context, name, owner string, token int64, ttl time.Duration) (bool, error)
+	// Release expires the lease immediately if owner still holds it with token
+	Release(ctx context.Context, name, owner string, token int64) error
+	// Holds reports whether owner still holds the unexpired lease with token
+	Holds(ctx context.Context, name, owner string, token int64) (bool, error)
+	Get(ctx context.Context, name string) (*models.Lease, error)
+}
+
+type JobStatusRepository interface {
+	// Record stores the outcome of a run and bumps the run counters
+	Record(ctx context.Context, status *models.JobStatus) error
+	List(ctx context.Context) ([]models.JobStatus, error)
+}
+
+type ActivityLogRetentionRepository interface {
+	// DeleteBefore removes activity logs processed before the given time,
+	// except those under a legal hold, and returns how many were deleted and
+	// how many were kept for a hold
+	DeleteBefore(ctx context.Context, before time.Time, holds HoldScope) (deleted, held int64, err error)
+}
+
+type ActivityRollupRepository interface {
+	// Increment counts one stored activity log in its daily rollup
+	Increment(ctx context.Context, log *models.ActivityLog) error
+	// Rebuild recomputes the rollups of the days in [from, to) from the
+	// activity logs and returns how many rollup documents were written.
+	// Callers keep from within the retention window: purged days would be
+	// rebuilt as empty.
+	Rebuild(ctx context.Context, from, to time.Time) (int64, error)
+}
+
+// TimelineQuery selects the activity logs where a user is the actor or the
+// subject, newest first
+type TimelineQuery struct {
+	UserID        string
+	ActorFields   []string // payload fields holding the acting user
+	SubjectFields []string // payload fields holding the user acted upon
+	Topics        []string // all topics when empty
+	// Only logs strictly older than (BeforeTimestamp, BeforeID), for paging
+	BeforeTimestamp time.Time
+	BeforeID        primitive.ObjectID
+	Limit           int
+}
+
+type ActivityLogQueryRepository interface {
+	UserTimeline(ctx context.Context, query TimelineQuery) ([]models.ActivityLog, error)
+	// FindByEventID returns the log of eventID, or nil if none exists
+	FindByEventID(ctx context.Context, eventID string) (*models.ActivityLog, error)
+	// ProcessedBetween calls fn with every log processed in [from, to), in
+	// eventId order
+	ProcessedBetween(ctx context.Context, from, to time.Time, fn func(log *models.ActivityLog) error) error
+}
+
+type ActivityLogErasureRepository interface {
+	// FindReferencing returns up to limit logs, in _id order after afterID,
+	// holding userID at any of the payload paths, as subject or as an
+	// extension or header value, and not under a legal hold
+	FindReferencing(ctx context.Context, userID string, paths []string, holds HoldScope, afterID primitive.ObjectID, limit int) ([]models.ActivityLog, error)
+	// CountHeld counts the logs referencing userID that a legal hold keeps
+	CountHeld(ctx context.Context, userID string, paths []string, holds HoldScope) (int64, error)
+	// ReplaceUserFields overwrites the payload, subject, extensions and
+	// metadata of each log and returns how many logs were modified
+	ReplaceUserFields(ctx context.Context, logs []models.ActivityLog) (int64, error)
+	DeleteByIDs(ctx context.Context, ids []primitive.ObjectID) (int64, error)
+}
+
+type ErasureReceiptRepository interface {
+	// Get returns the receipt of subject, or nil if none exists
+	Get(ctx context.Context, subject string) (*models.ErasureReceipt, error)
+	Save(ctx context.Context, receipt *models.ErasureReceipt) error
+}
+
+type LegalHoldRepository interface {
+	Create(ctx context.Context, hold *models.LegalHold) error
+	// List returns the holds in creation order, only active ones unless
+	// includeReleased is set
+	List(ctx context.Context, includeReleased bool) ([]models.LegalHold, error)
+	// Release ends an active hold and reports whether one was released
+	Release(ctx context.Context, id primitive.ObjectID, releasedBy string) (bool, error)
+}
+
+type DataKeyRepository interface {
+	Create(ctx context.Context, key *models.DataKey) error
+	// Get returns the data key id, or nil if none exists
+	Get(ctx context.Context, id string) (*models.DataKey, error)
+	// Latest returns the newest data key, or nil if none exists
+	Latest(ctx context.Context) (*models.DataKey, error)
+	List(ctx context.Context) ([]models.DataKey, error)
+	// Rewrap replaces the wrapped form of a data key after a master key rotation
+	Rewrap(ctx context.Context, id, masterKeyID string, wrapped []byte) error
+}
+
+type AuditChainRepository interface {
+	// Partitions returns the hash chain partitions, sorted
+	Partitions(ctx context.Context) ([]string, error)
+	// Walk calls fn with every chained log of partition in sequence order
+	Walk(ctx context.Context, partition string, fn func(log *models.ActivityLog) error) error
+	// Head returns the recorded head of partition, or nil if none exists
+	Head(ctx context.Context, partition string) (*models.ChainHead, error)
+	// RecordTombstones stores tombstones ahead of their action; an event's
+	// first tombstone keeps its link and content hash
+	RecordTombstones(ctx context.Context, tombstones []models.ChainTombstone) error
+	// WalkTombstones calls fn with the tombstones of partition with a seq in
+	// [fromSeq, toSeq], in sequence order
+	WalkTombstones(ctx context.Context, partition string, fromSeq, toSeq int64, fn func(tombstone *models.ChainTombstone) error) error
+	// Tombstone returns the tombstone of eventID, or nil if none exists
+	Tombstone(ctx context.Context, eventID string) (*models.ChainTombstone, error)
+}
+
+type AuditCheckpointRepository interface {
+	Create(ctx context.Context, checkpoint *models.AuditCheckpoint) error
+	Get(ctx context.Context, id string) (*models.AuditCheckpoint, error)
+	// Latest returns the newest checkpoint, or nil if none exists
+	Latest(ctx context.Context) (*models.AuditCheckpoint, error)
+	// CreateLeaves stores the ordered leaves of a checkpoint
+	CreateLeaves(ctx context.Context, leaves []models.AuditCheckpointLeaf) error
+	// DeleteLeaves removes the leaves of a checkpoint that was not stored
+	DeleteLeaves(ctx context.Context, checkpointID string) error
+	// Leaves returns the leaves of a checkpoint in index order
+	Leaves(ctx context.Context, checkpointID string) ([]models.AuditCheckpointLeaf, error)
+	// FindLeaf returns the leaf of eventID in checkpointID, or in the newest
+	// checkpoint containing it when checkpointID is empty; nil if none
+	FindLeaf(ctx context.Context, eventID, checkpointID string) (*models.AuditCheckpointLeaf, error)
+}
+
+type QuarantineRepository interface {
+	// Create stores event unless the same handler already quarantined it
+	Create(ctx context.Context, event *models.QuarantinedEvent) error
+}
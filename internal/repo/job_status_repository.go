@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type jobStatusRepository struct {
+	collection *mongo.Collection
+}
+
+func NewJobStatusRepository() JobStatusRepository {
+	collection := global.MongoDB.Collection(common.JobStatusCollection)
+	return &jobStatusRepository{
+		collection: collection,
+	}
+}
+
+func (r *jobStatusRepository) Record(ctx context.Context, status *models.JobStatus) error {
+	failures := 0
+	if status.LastResult != models.JobResultSuccess {
+		failures = 1
+	}
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": status.Name},
+		bson.M{
+			"$set": bson.M{
+				"schedule":       status.Schedule,
+				"lastStartedAt":  status.LastStartedAt,
+				"lastFinishedAt": status.LastFinishedAt,
+				"lastDurationMs": status.LastDurationMs,
+				"lastResult":     status.LastResult,
+				"lastError":      status.LastError,
+				"lastInstance":   status.LastInstance,
+				"lastTrigger":    status.LastTrigger,
+			},
+			"$inc": bson.M{
+				"runCount":     int64(1),
+				"failureCount": int64(failures),
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return nil
+}
+
+func (r *jobStatusRepository) List(ctx context.Context) ([]models.JobStatus, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	var statuses []models.JobStatus
+	if err := cursor.All(ctx, &statuses); err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return statuses, nil
+}
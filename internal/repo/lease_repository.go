@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type leaseRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLeaseRepository() LeaseRepository {
+	collection := global.MongoDB.Collection(common.LeaseCollection)
+	return &leaseRepository{
+		collection: collection,
+	}
+}
+
+func (r *leaseRepository) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (*models.Lease, bool, error) {
+	now := time.Now()
+
+	// Only matches an expired lease; when the lease is held the upsert
+	// collides with the existing _id instead
+	filter := bson.M{
+		"_id":       name,
+		"expiresAt": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"owner":      owner,
+			"expiresAt":  now.Add(ttl),
+			"acquiredAt": now,
+			"renewedAt":  now,
+		},
+		"$inc": bson.M{"token": int64(1)},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var lease models.Lease
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&lease)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return &lease, true, nil
+}
+
+func (r *leaseRepository) Renew(ctx context.Context, name, owner string, token int64, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": name, "owner": owner, "token": token},
+		bson.M{"$set": bson.M{"expiresAt": now.Add(ttl), "renewedAt": now}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (r *leaseRepository) Release(ctx context.Context, name, owner string, token int64) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": name, "owner": owner, "token": token},
+		bson.M{"$set": bson.M{"expiresAt": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return nil
+}
+
+func (r *leaseRepository) Holds(ctx context.Context, name, owner string, token int64) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"_id":       name,
+		"owner":     owner,
+		"token":     token,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return count == 1, nil
+}
+
+func (r *leaseRepository) Get(ctx context.Context, name string) (*models.Lease, error) {
+	var lease models.Lease
+	err := r.collection.FindOne(ctx, bson.M{"_id": name}).Decode(&lease)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return &lease, nil
+}
@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type legalHoldRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLegalHoldRepository() LegalHoldRepository {
+	collection := global.MongoDB.Collection(common.LegalHoldCollection)
+	return &legalHoldRepository{
+		collection: collection,
+	}
+}
+
+func (r *legalHoldRepository) Create(ctx context.Context, hold *models.LegalHold) error {
+	result, err := r.collection.InsertOne(ctx, hold)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		hold.ID = id
+	}
+	return nil
+}
+
+func (r *legalHoldRepository) List(ctx context.Context, includeReleased bool) ([]models.LegalHold, error) {
+	filter := bson.M{}
+	if !includeReleased {
+		filter["releasedAt"] = bson.M{"$exists": false}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	var holds []models.LegalHold
+	if err := cursor.All(ctx, &holds); err != nil {
+		return nil, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return holds, nil
+}
+
+func (r *legalHoldRepository) Release(ctx context.Context, id primitive.ObjectID, releasedBy string) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "releasedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"releasedBy": releasedBy, "releasedAt": time.Now()}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
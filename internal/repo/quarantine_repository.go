@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type quarantineRepository struct {
+	collection *mongo.Collection
+}
+
+func NewQuarantineRepository() QuarantineRepository {
+	collection := global.MongoDB.Collection(common.QuarantineCollection)
+	return &quarantineRepository{
+		collection: collection,
+	}
+}
+
+func (r *quarantineRepository) Create(ctx context.Context, event *models.QuarantinedEvent) error {
+	if event.EventID == "" {
+		result, err := r.collection.InsertOne(ctx, event)
+		if err != nil {
+			return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+		}
+
+		if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+			event.ID = id
+		}
+		return nil
+	}
+
+	// Redeliveries keep the first quarantine of the event
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"eventId": event.EventID, "handler": event.Handler},
+		bson.M{"$setOnInsert": event},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	if result != nil {
+		if id, ok := result.UpsertedID.(primitive.ObjectID); ok {
+			event.ID = id
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"event_service/internal/common"
+	"event_service/internal/metrics"
+	"event_service/internal/models"
+	"event_service/internal/spool"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	spoolAppendedTotal = metrics.NewCounter("event_service_spool_appended_total",
+		"Activity logs written to the local spool because MongoDB was unavailable")
+	spoolAppendFailedTotal = metrics.NewCounter("event_service_spool_append_failed_total",
+		"Activity logs that could not be spooled", "reason")
+)
+
+// spoolingActivityLogRepository falls back to the local disk spool when the
+// wrapped repository cannot reach MongoDB, so the event can still be acked.
+// Spooled records are replayed by services.SpoolDrainer.
+type spoolingActivityLogRepository struct {
+	next  ActivityLogRepository
+	spool *spool.Spool
+}
+
+func NewSpoolingActivityLogRepository(next ActivityLogRepository, s *spool.Spool) ActivityLogRepository {
+	return &spoolingActivityLogRepository{
+		next:  next,
+		spool: s,
+	}
+}
+
+func (r *spoolingActivityLogRepository) Create(ctx context.Context, log *models.ActivityLog) error {
+	err := r.next.Create(ctx, log)
+	if err == nil || !IsConnectivityError(err) {
+		return err
+	}
+
+	data, marshalErr := bson.Marshal(log)
+	if marshalErr != nil {
+		spoolAppendFailedTotal.Inc("marshal")
+		fmt.Printf("Failed to encode event %s for spool: %v\n", log.EventID, marshalErr)
+		return err
+	}
+
+	if spoolErr := r.spool.Append(data); spoolErr != nil {
+		reason := "write"
+		if errors.Is(spoolErr, common.ErrSpoolFull) {
+			reason = "full"
+		}
+		spoolAppendFailedTotal.Inc(reason)
+		fmt.Printf("Failed to spool event %s: %v\n", log.EventID, spoolErr)
+		return err
+	}
+
+	spoolAppendedTotal.Inc()
+	fmt.Printf("MongoDB unavailable, event %s spooled to disk: %v\n", log.EventID, err)
+	return nil
+}
@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type streamOffsetRepository struct {
+	collection *mongo.Collection
+}
+
+func NewStreamOffsetRepository() StreamOffsetRepository {
+	collection := global.MongoDB.Collection(common.StreamOffsetCollection)
+	return &streamOffsetRepository{
+		collection: collection,
+	}
+}
+
+func (r *streamOffsetRepository) Get(ctx context.Context, consumer string) (int64, bool, error) {
+	var offset models.StreamOffset
+	err := r.collection.FindOne(ctx, bson.M{"_id": consumer}).Decode(&offset)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("%w: %w", common.ErrMongoQuery, err)
+	}
+
+	return offset.Offset, true, nil
+}
+
+func (r *streamOffsetRepository) Save(ctx context.Context, consumer string, offset int64) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": consumer},
+		bson.M{"$set": bson.M{"offset": offset, "updatedAt": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrMongoInsert, err)
+	}
+
+	return nil
+}
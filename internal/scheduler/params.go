@@ -0,0 +1,38 @@
+package scheduler
+
+import "fmt"
+
+// IntParam reads an integer job parameter, falling back to def when unset
+func IntParam(params map[string]interface{}, key string, def int) (int, error) {
+	value, exists := params[key]
+	if !exists {
+		return def, nil
+	}
+
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+
+	return 0, fmt.Errorf("param %s must be an integer, got %v", key, value)
+}
+
+// StringParam reads a string job parameter, falling back to def when unset
+func StringParam(params map[string]interface{}, key, def string) (string, error) {
+	value, exists := params[key]
+	if !exists {
+		return def, nil
+	}
+
+	if v, ok := value.(string); ok {
+		return v, nil
+	}
+
+	return "", fmt.Errorf("param %s must be a string, got %v", key, value)
+}
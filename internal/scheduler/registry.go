@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"event_service/internal/common"
+	"event_service/internal/lease"
+)
+
+// JobFunc is one run of a scheduled job. It should return promptly once
+// ctx is done, and check fence right before each write so a run from a
+// stale lease holder cannot overwrite the current one's.
+type JobFunc func(ctx context.Context, fence lease.Fence) error
+
+// JobFactory creates a job from the params configured for it
+type JobFactory func(params map[string]interface{}) (JobFunc, error)
+
+var (
+	jobsMu    sync.RWMutex
+	factories = make(map[string]JobFactory)
+)
+
+// RegisterJob makes a job available to the scheduler configuration under
+// name, replacing any job previously registered with that name
+func RegisterJob(name string, factory JobFactory) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+
+	factories[name] = factory
+}
+
+// NewJob creates the job registered under name
+func NewJob(name string, params map[string]interface{}) (JobFunc, error) {
+	jobsMu.RLock()
+	factory, exists := factories[name]
+	jobsMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s (registered: %v)", common.ErrUnknownJob, name, JobNames())
+	}
+
+	return factory(params)
+}
+
+// JobNames returns the registered job names in a stable order
+func JobNames() []string {
+	jobsMu.RLock()
+	defer jobsMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
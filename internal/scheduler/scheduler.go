@@ -0,0 +1,330 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/lease"
+	"event_service/internal/metrics"
+	"event_service/internal/models"
+	"event_service/internal/repo"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+
+	defaultJobTimeout = 5 * time.Minute
+)
+
+var (
+	jobRuns = metrics.NewCounter("event_service_job_runs_total",
+		"Scheduled job runs by result (success, failed, timeout, skipped)", "job", "result")
+	jobDuration = metrics.NewGauge("event_service_job_last_duration_seconds",
+		"Duration of the last run of a scheduled job", "job")
+)
+
+// JobInfo is what the scheduler knows about a job on this instance together
+// with the last recorded run across all instances
+type JobInfo struct {
+	Name      string            `json:"name"`
+	Schedule  string            `json:"schedule"`
+	Running   bool              `json:"running"`
+	Leader    bool              `json:"leader"` // this instance may run it
+	NextRunAt time.Time         `json:"nextRunAt"`
+	LastRun   *models.JobStatus `json:"lastRun,omitempty"`
+}
+
+type job struct {
+	name     string
+	schedule string
+	timeout  time.Duration
+	run      JobFunc
+	lease    *lease.Lease // nil when the job runs on every instance
+	entryID  cron.EntryID
+	running  atomic.Bool
+}
+
+// Scheduler runs the configured jobs on their cron schedules. Singleton jobs
+// only run on the instance holding their lease, and a job never overlaps
+// with its own previous run on the same instance.
+type Scheduler struct {
+	cron       *cron.Cron
+	jobs       map[string]*job
+	names      []string
+	repository repo.JobStatusRepository
+	ctx        context.Context // cancelled when shutdown gives up waiting for runs
+	cancel     context.CancelFunc
+}
+
+var (
+	currentMu sync.RWMutex
+	current   *Scheduler
+)
+
+// Current returns the running scheduler, or nil if none was started
+func Current() *Scheduler {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+
+	return current
+}
+
+// New builds a scheduler from the scheduler.jobs configuration. Disabled
+// jobs are skipped; unknown jobs and invalid schedules are errors.
+func New() (*Scheduler, error) {
+	cfg := global.Config.Scheduler
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		cron:       cron.New(cron.WithLocation(time.UTC)),
+		jobs:       make(map[string]*job),
+		repository: repo.NewJobStatusRepository(),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+
+	names := make([]string, 0, len(cfg.Jobs))
+	for name := range cfg.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		jobCfg := cfg.Jobs[name]
+		if !jobCfg.Enabled {
+			continue
+		}
+
+		jobName := jobCfg.Job
+		if jobName == "" {
+			jobName = name
+		}
+
+		run, err := NewJob(jobName, jobCfg.Params)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", name, err)
+		}
+
+		j := &job{
+			name:     name,
+			schedule: jobCfg.Schedule,
+			timeout:  time.Duration(jobCfg.TimeoutSeconds) * time.Second,
+			run:      run,
+		}
+		if j.timeout <= 0 {
+			j.timeout = defaultJobTimeout
+		}
+		if !jobCfg.AllInstances {
+			j.lease = lease.New("job:" + name)
+		}
+
+		j.entryID, err = s.cron.AddFunc(jobCfg.Schedule, func() {
+			err := s.execute(s.ctx, j, TriggerSchedule, false)
+			if err != nil && !errors.Is(err, common.ErrNotLeader) {
+				fmt.Printf("Scheduled job %s: %v\n", j.name, err)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("job %s: invalid schedule %q: %v", name, jobCfg.Schedule, err)
+		}
+
+		s.jobs[name] = j
+		s.names = append(s.names, name)
+	}
+
+	return s, nil
+}
+
+// Start starts campaigning for job leases and running jobs on schedule
+func (s *Scheduler) Start() {
+	for _, name := range s.names {
+		if j := s.jobs[name]; j.lease != nil {
+			j.lease.Start()
+		}
+	}
+
+	s.cron.Start()
+
+	currentMu.Lock()
+	current = s
+	currentMu.Unlock()
+
+	fmt.Printf("Scheduler started with jobs: %v\n", s.names)
+}
+
+// Stop stops scheduling new runs and waits for running jobs until ctx is
+// done, then cancels them. Job leases are released with the other leases on
+// shutdown.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	currentMu.Lock()
+	if current == s {
+		current = nil
+	}
+	currentMu.Unlock()
+
+	select {
+	case <-s.cron.Stop().Done():
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return fmt.Errorf("scheduled jobs still running at shutdown: %w", ctx.Err())
+	}
+}
+
+// Trigger runs a job now, outside its schedule, and returns its error.
+// Singleton jobs only run on the lease holder.
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	j, exists := s.jobs[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", common.ErrUnknownJob, name)
+	}
+
+	return s.execute(ctx, j, TriggerManual, false)
+}
+
+// RunLocally runs a job once in this process, for operators running it by
+// hand. Singleton jobs take their lease for the run, so they fail with
+// ErrNotLeader while a replica holds it. The scheduler must not be started.
+func (s *Scheduler) RunLocally(ctx context.Context, name string) error {
+	j, exists := s.jobs[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", common.ErrUnknownJob, name)
+	}
+
+	return s.execute(ctx, j, TriggerManual, true)
+}
+
+// Jobs describes every scheduled job along with its last recorded run
+func (s *Scheduler) Jobs(ctx context.Context) ([]JobInfo, error) {
+	statuses, err := s.repository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lastRuns := make(map[string]*models.JobStatus)
+	for i := range statuses {
+		lastRuns[statuses[i].Name] = &statuses[i]
+	}
+
+	infos := make([]JobInfo, 0, len(s.names))
+	for _, name := range s.names {
+		j := s.jobs[name]
+		infos = append(infos, JobInfo{
+			Name:      name,
+			Schedule:  j.schedule,
+			Running:   j.running.Load(),
+			Leader:    j.lease == nil || j.lease.IsLeader(),
+			NextRunAt: s.cron.Entry(j.entryID).Next,
+			LastRun:   lastRuns[name],
+		})
+	}
+
+	return infos, nil
+}
+
+func (s *Scheduler) execute(ctx context.Context, j *job, trigger string, local bool) error {
+	if !local && j.lease != nil && !j.lease.IsLeader() {
+		return common.ErrNotLeader
+	}
+
+	if !j.running.CompareAndSwap(false, true) {
+		jobRuns.Inc(j.name, "skipped")
+		fmt.Printf("Skipping job %s: previous run still in progress\n", j.name)
+		return common.ErrJobRunning
+	}
+	defer j.running.Store(false)
+
+	status := &models.JobStatus{
+		Name:          j.name,
+		Schedule:      j.schedule,
+		LastStartedAt: time.Now(),
+		LastInstance:  global.InstanceID,
+		LastTrigger:   trigger,
+	}
+
+	run := func(ctx context.Context, fence lease.Fence) error {
+		return runJob(ctx, j, fence)
+	}
+
+	var err error
+	if j.lease == nil {
+		err = run(ctx, lease.Fence{})
+	} else {
+		var ran bool
+		if local {
+			ran, err = j.lease.RunOnce(ctx, run)
+		} else {
+			ran, err = j.lease.Run(ctx, run)
+		}
+		if !ran {
+			if err != nil {
+				return err
+			}
+			return common.ErrNotLeader
+		}
+	}
+
+	return s.record(j, status, err)
+}
+
+// runJob runs j with its timeout, turning a panic into an error
+func runJob(ctx context.Context, j *job, fence lease.Fence) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, j.timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("Job %s panicked: %v\n%s", j.name, r, debug.Stack())
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	fmt.Printf("Running job %s...\n", j.name)
+	err = j.run(ctx, fence)
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+func (s *Scheduler) record(j *job, status *models.JobStatus, runErr error) error {
+	status.LastFinishedAt = time.Now()
+	duration := status.LastFinishedAt.Sub(status.LastStartedAt)
+	status.LastDurationMs = duration.Milliseconds()
+
+	switch {
+	case runErr == nil:
+		status.LastResult = models.JobResultSuccess
+	case errors.Is(runErr, context.DeadlineExceeded):
+		status.LastResult = models.JobResultTimeout
+		status.LastError = runErr.Error()
+	default:
+		status.LastResult = models.JobResultFailed
+		status.LastError = runErr.Error()
+	}
+
+	jobRuns.Inc(j.name, status.LastResult)
+	jobDuration.Set(duration.Seconds(), j.name)
+	fmt.Printf("Job %s finished: %s in %s\n", j.name, status.LastResult, duration)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.repository.Record(ctx, status); err != nil {
+		fmt.Printf("Error recording run of job %s: %v\n", j.name, err)
+	}
+
+	return runErr
+}
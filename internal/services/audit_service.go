@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"event_service/internal/dto"
+	"event_service/internal/models"
+	"event_service/internal/repo"
+)
+
+type auditService struct {
+	chainRepo repo.AuditChainRepository
+}
+
+func NewAuditService() AuditService {
+	return &auditService{
+		chainRepo: repo.NewAuditChainRepository(),
+	}
+}
+
+func (s *auditService) VerifyChain(ctx context.Context, partition string) ([]dto.ChainReport, error) {
+	partitions := []string{partition}
+	if partition == "" {
+		var err error
+		if partitions, err = s.chainRepo.Partitions(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reports := make([]dto.ChainReport, 0, len(partitions))
+	for _, name := range partitions {
+		report, err := s.verifyPartition(ctx, name)
+		if err != nil {
+			return reports, fmt.Errorf("partition %s: %w", name, err)
+		}
+		reports = append(reports, *report)
+	}
+
+	return reports, nil
+}
+
+func (s *auditService) verifyPartition(ctx context.Context, partition string) (*dto.ChainReport, error) {
+	report := &dto.ChainReport{Partition: partition}
+	// previous is the link the next log must follow, nil when unknown
+	// because the log before it is gone without a tombstone
+	var previous *models.ChainLink
+	var lastSeq int64
+
+	err := s.chainRepo.Walk(ctx, partition, func(log *models.ActivityLog) error {
+		link := log.Chain
+		report.Records++
+		report.LastSeq = link.Seq
+		if report.Records == 1 {
+			report.FirstSeq = link.Seq
+		}
+
+		if link.Seq > lastSeq+1 {
+			gone := dto.ChainIssueMissing
+			if lastSeq == 0 {
+				gone = dto.ChainIssueStartsLater
+			}
+			var err error
+			if previous, err = s.bridge(ctx, report, partition, previous, lastSeq+1, link.Seq-1, gone); err != nil {
+				return err
+			}
+		}
+		lastSeq = link.Seq
+
+		issue := func(kind, detail string) {
+			report.Issues = append(report.Issues, dto.ChainIssue{Kind: kind, Seq: link.Seq, EventID: log.EventID, Detail: detail})
+		}
+
+		contentHash, err := repo.ChainContentHash(log)
+		if err != nil {
+			return err
+		}
+		if contentHash != link.ContentHash {
+			kind, detail, err := s.rewritten(ctx, log, contentHash)
+			if err != nil {
+				return err
+			}
+			issue(kind, detail)
+		}
+		if repo.ChainLinkHash(partition, link.Seq, link.ContentHash, link.PrevHash) != link.Hash {
+			issue(dto.ChainIssueTampered, "link hash does not match the link")
+		}
+		if previous != nil && link.PrevHash != previous.Hash {
+			issue(dto.ChainIssueBrokenLink, fmt.Sprintf("prevHash does not match the hash of log %d", previous.Seq))
+		}
+
+		previous = link
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := s.chainRepo.Head(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return report, nil
+	}
+
+	report.HeadSeq = head.Seq
+	if head.Seq > lastSeq {
+		if previous, err = s.bridge(ctx, report, partition, previous, lastSeq+1, head.Seq, dto.ChainIssueTruncated); err != nil {
+			return nil, err
+		}
+		lastSeq = head.Seq
+	}
+	if head.Seq == lastSeq && previous != nil && head.Hash != previous.Hash {
+		report.Issues = append(report.Issues, dto.ChainIssue{
+			Kind: dto.ChainIssueBrokenLink, Seq: head.Seq,
+			Detail: "the newest log does not match the recorded head",
+		})
+	}
+
+	return report, nil
+}
+
+// rewritten explains a log whose content no longer matches its hash: a
+// notice when a tombstone records the rewrite down to the current content,
+// tampering otherwise
+func (s *auditService) rewritten(ctx context.Context, log *models.ActivityLog, contentHash string) (string, string, error) {
+	tombstone, err := s.chainRepo.Tombstone(ctx, log.EventID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if tombstone != nil && tombstone.Action == models.TombstoneAnonymized &&
+		tombstone.ContentHash == log.Chain.ContentHash && tombstone.NewContentHash == contentHash {
+		return dto.ChainIssueAnonymized, "rewritten by " + tombstone.Reference, nil
+	}
+	return dto.ChainIssueModified, "content does not match its hash", nil
+}
+
+// gapRun is a run of consecutive seqs gone from a partition for the same
+// reason
+type gapRun struct {
+	kind        string
+	reference   string
+	first, last int64
+}
+
+func (r *gapRun) issue() dto.ChainIssue {
+	detail := fmt.Sprintf("logs %d-%d are gone", r.first, r.last)
+	switch r.kind {
+	case dto.ChainIssueErased:
+		detail = fmt.Sprintf("logs %d-%d were deleted by %s", r.first, r.last, r.reference)
+	case dto.ChainIssuePurged:
+		detail = fmt.Sprintf("logs %d-%d were purged by %s", r.first, r.last, r.reference)
+	}
+	return dto.ChainIssue{Kind: r.kind, Seq: r.first, Detail: detail}
+}
+
+// bridge checks the seqs from..to, whose logs are gone, against their
+// tombstones and reports each run by what happened to it; seqs without a
+// tombstone are reported as gone. previous is the link before from, nil
+// when unknown. It returns the link the next log must follow.
+func (s *auditService) bridge(ctx context.Context, report *dto.ChainReport, partition string, previous *models.ChainLink, from, to int64, gone string) (*models.ChainLink, error) {
+	var run *gapRun
+	flush := func() {
+		if run != nil {
+			report.Issues = append(report.Issues, run.issue())
+			run = nil
+		}
+	}
+	add := func(first, last int64, kind, reference string) {
+		if run != nil && run.kind == kind && run.reference == reference && run.last == first-1 {
+			run.last = last
+			return
+		}
+		flush()
+		run = &gapRun{kind: kind, reference: reference, first: first, last: last}
+	}
+
+	next := from
+	err := s.chainRepo.WalkTombstones(ctx, partition, from, to, func(tombstone *models.ChainTombstone) error {
+		if tombstone.Seq > next {
+			add(next, tombstone.Seq-1, gone, "")
+			previous = nil
+		}
+		next = tombstone.Seq + 1
+
+		kind := dto.ChainIssuePurged
+		switch tombstone.Action {
+		case models.TombstoneErased:
+			kind = dto.ChainIssueErased
+		case models.TombstoneAnonymized:
+			// Only the rewrite was recorded, not the deletion
+			add(tombstone.Seq, tombstone.Seq, gone, "")
+			previous = nil
+			return nil
+		}
+
+		issue := func(kind, detail string) {
+			flush()
+			report.Issues = append(report.Issues, dto.ChainIssue{Kind: kind, Seq: tombstone.Seq, EventID: tombstone.EventID, Detail: detail})
+		}
+		if repo.ChainLinkHash(partition, tombstone.Seq, tombstone.ContentHash, tombstone.PrevHash) != tombstone.Hash {
+			issue(dto.ChainIssueTampered, "tombstone link hash does not match the link")
+		}
+		if previous != nil && tombstone.PrevHash != previous.Hash {
+			issue(dto.ChainIssueBrokenLink, fmt.Sprintf("tombstone prevHash does not match the hash of log %d", previous.Seq))
+		}
+
+		add(tombstone.Seq, tombstone.Seq, kind, tombstone.Reference)
+		previous = &models.ChainLink{
+			Partition:   partition,
+			Seq:         tombstone.Seq,
+			ContentHash: tombstone.ContentHash,
+			PrevHash:    tombstone.PrevHash,
+			Hash:        tombstone.Hash,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if next <= to {
+		add(next, to, gone, "")
+		previous = nil
+	}
+	flush()
+
+	return previous, nil
+}
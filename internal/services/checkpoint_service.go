@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/audit"
+	"event_service/internal/common"
+	"event_service/internal/dto"
+	"event_service/internal/lease"
+	"event_service/internal/models"
+	"event_service/internal/repo"
+)
+
+type checkpointService struct {
+	checkpointRepo repo.AuditCheckpointRepository
+	queryRepo      repo.ActivityLogQueryRepository
+	chainRepo      repo.AuditChainRepository
+}
+
+func NewCheckpointService() CheckpointService {
+	return &checkpointService{
+		checkpointRepo: repo.NewAuditCheckpointRepository(),
+		queryRepo:      repo.NewActivityLogQueryRepository(),
+		chainRepo:      repo.NewAuditChainRepository(),
+	}
+}
+
+func (s *checkpointService) CreateCheckpoint(ctx context.Context, firstWindow, settle time.Duration, fence lease.Fence) (*models.AuditCheckpoint, error) {
+	config := global.Config.Audit
+	signer, err := audit.NewSigner(config.CheckpointKeyID, config.CheckpointSigningKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mongo keeps milliseconds; truncate so the stored bounds sign the same
+	to := time.Now().Add(-settle).UTC().Truncate(time.Millisecond)
+	checkpoint := &models.AuditCheckpoint{
+		ID:   "cp-" + to.Format("20060102T150405.000Z"),
+		From: to.Add(-firstWindow),
+		To:   to,
+	}
+
+	latest, err := s.checkpointRepo.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil {
+		checkpoint.From = latest.To
+		checkpoint.PrevID = latest.ID
+		checkpoint.PrevRoot = latest.Root
+	}
+	if !checkpoint.From.Before(checkpoint.To) {
+		return nil, nil
+	}
+
+	leaves, err := s.leaves(ctx, checkpoint.ID, checkpoint.From, checkpoint.To)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint.Leaves = int64(len(leaves))
+	checkpoint.Root = hex.EncodeToString(audit.RootHash(leafHashes(leaves)))
+	checkpoint.CreatedAt = time.Now()
+	signer.Sign(checkpoint)
+
+	if err := fence.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	// Leaves first: a stored checkpoint always has its leaves to prove from
+	if err := s.checkpointRepo.CreateLeaves(ctx, leaves); err != nil {
+		return nil, err
+	}
+	if err := s.checkpointRepo.Create(ctx, checkpoint); err != nil {
+		if cleanupErr := s.checkpointRepo.DeleteLeaves(ctx, checkpoint.ID); cleanupErr != nil {
+			fmt.Printf("Failed to remove leaves of unsaved checkpoint %s: %v\n", checkpoint.ID, cleanupErr)
+		}
+		return nil, err
+	}
+
+	fmt.Printf("Audit checkpoint %s signed: %d logs, root %s\n", checkpoint.ID, checkpoint.Leaves, checkpoint.Root)
+	return checkpoint, nil
+}
+
+func (s *checkpointService) Prove(ctx context.Context, eventID, checkpointID string) (*dto.InclusionProof, error) {
+	leaf, err := s.checkpointRepo.FindLeaf(ctx, eventID, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	if leaf == nil {
+		return nil, fmt.Errorf("%w: none covers event %s", common.ErrCheckpointNotFound, eventID)
+	}
+
+	checkpoint, err := s.checkpointRepo.Get(ctx, leaf.CheckpointID)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrCheckpointNotFound, leaf.CheckpointID)
+	}
+
+	if err := audit.VerifySignature(checkpoint); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrCheckpointMismatch, err)
+	}
+
+	stored, err := s.checkpointRepo.Leaves(ctx, checkpoint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The proof is built from the leaves as signed, so erasing or purging
+	// other logs of the window does not affect it. Only tampering with the
+	// stored leaves changes the root.
+	leaves := leafHashes(stored)
+	if root := hex.EncodeToString(audit.RootHash(leaves)); root != checkpoint.Root {
+		return nil, fmt.Errorf("%w: checkpoint %s root %s, stored leaves %s", common.ErrCheckpointMismatch, checkpoint.ID, checkpoint.Root, root)
+	}
+
+	index := int(leaf.Index)
+	if index < 0 || index >= len(stored) || stored[index].EventID != eventID {
+		return nil, fmt.Errorf("%w: leaf %d of checkpoint %s is not event %s", common.ErrCheckpointMismatch, leaf.Index, checkpoint.ID, eventID)
+	}
+
+	logStatus, err := s.logStatus(ctx, &stored[index])
+	if err != nil {
+		return nil, err
+	}
+
+	path := audit.InclusionPath(leaves, index)
+	proof := &dto.InclusionProof{
+		EventID:      eventID,
+		CheckpointID: checkpoint.ID,
+		LeafIndex:    index,
+		TreeSize:     len(leaves),
+		ContentHash:  stored[index].ContentHash,
+		LeafHash:     hex.EncodeToString(leaves[index]),
+		Path:         make([]string, 0, len(path)),
+		Root:         checkpoint.Root,
+		KeyID:        checkpoint.KeyID,
+		PublicKey:    checkpoint.PublicKey,
+		Signature:    checkpoint.Signature,
+		LogStatus:    logStatus,
+	}
+	for _, node := range path {
+		proof.Path = append(proof.Path, hex.EncodeToString(node))
+	}
+
+	return proof, nil
+}
+
+// logStatus compares the live log with the content hash it was signed
+// with. A change recorded by a tombstone is reported as what it was.
+func (s *checkpointService) logStatus(ctx context.Context, leaf *models.AuditCheckpointLeaf) (string, error) {
+	log, err := s.queryRepo.FindByEventID(ctx, leaf.EventID)
+	if err != nil {
+		return "", err
+	}
+
+	var contentHash string
+	if log != nil {
+		if contentHash, err = repo.ChainContentHash(log); err != nil {
+			return "", err
+		}
+		if contentHash == leaf.ContentHash {
+			return dto.LogStatusUnchanged, nil
+		}
+	}
+
+	tombstone, err := s.chainRepo.Tombstone(ctx, leaf.EventID)
+	if err != nil {
+		return "", err
+	}
+	// The leaf may have been signed before or after an earlier rewrite
+	recorded := tombstone != nil &&
+		(tombstone.ContentHash == leaf.ContentHash || tombstone.NewContentHash == leaf.ContentHash)
+
+	switch {
+	case log == nil && recorded && tombstone.Action == models.TombstoneErased:
+		return dto.LogStatusErased, nil
+	case log == nil && recorded && tombstone.Action == models.TombstonePurged:
+		return dto.LogStatusPurged, nil
+	case log == nil:
+		return dto.LogStatusDeleted, nil
+	case recorded && tombstone.Action == models.TombstoneAnonymized && tombstone.NewContentHash == contentHash:
+		return dto.LogStatusAnonymized, nil
+	}
+	return dto.LogStatusModified, nil
+}
+
+// leaves returns the leaves of checkpointID: the logs processed in
+// [from, to) with their content hash, in the order they are hashed
+func (s *checkpointService) leaves(ctx context.Context, checkpointID string, from, to time.Time) ([]models.AuditCheckpointLeaf, error) {
+	var leaves []models.AuditCheckpointLeaf
+
+	err := s.queryRepo.ProcessedBetween(ctx, from, to, func(log *models.ActivityLog) error {
+		contentHash, err := repo.ChainContentHash(log)
+		if err != nil {
+			return err
+		}
+		leaves = append(leaves, models.AuditCheckpointLeaf{
+			CheckpointID: checkpointID,
+			Index:        int64(len(leaves)),
+			EventID:      log.EventID,
+			ContentHash:  contentHash,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leaves, nil
+}
+
+// leafHashes returns the Merkle leaf hashes of stored leaves
+func leafHashes(leaves []models.AuditCheckpointLeaf) [][]byte {
+	hashes := make([][]byte, 0, len(leaves))
+	for _, leaf := range leaves {
+		hashes = append(hashes, audit.CheckpointLeaf(leaf.EventID, leaf.ContentHash))
+	}
+	return hashes
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/models"
+	"event_service/internal/repo"
+	"event_service/pkg/setting"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ErasureModeAnonymize = "anonymize"
+	ErasureModeDelete    = "delete"
+
+	defaultErasureBatchSize = 500
+)
+
+var defaultErasureFieldPaths = []string{"createdById", "actorId", "userId", "memberId"}
+
+type erasureService struct {
+	logRepo     repo.ActivityLogErasureRepository
+	receiptRepo repo.ErasureReceiptRepository
+	chainRepo   repo.AuditChainRepository
+	holds       LegalHoldService
+	mode        string
+	fieldPaths  []string
+	salt        []byte
+	batchSize   int
+}
+
+func NewErasureService() ErasureService {
+	cfg := global.Config.Erasure
+
+	mode := cfg.Mode
+	if mode == "" {
+		mode = ErasureModeAnonymize
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultErasureBatchSize
+	}
+
+	// Validated when the configuration was loaded or by the erase command
+	if err := ValidateErasureConfig(cfg); err != nil {
+		panic(fmt.Errorf("%w: %v", common.ErrConfigValidation, err))
+	}
+
+	return &erasureService{
+		logRepo:     repo.NewActivityLogErasureRepository(),
+		receiptRepo: repo.NewErasureReceiptRepository(),
+		chainRepo:   repo.NewAuditChainRepository(),
+		holds:       NewLegalHoldService(),
+		mode:        mode,
+		fieldPaths:  userFieldPaths(),
+		salt:        []byte(cfg.PseudonymSalt),
+		batchSize:   batchSize,
+	}
+}
+
+// ValidateErasureConfig checks what erasure needs to run. Without a salt
+// the pseudonym is a plain hash of the user id, which anyone can reverse by
+// hashing candidate ids.
+func ValidateErasureConfig(cfg setting.Erasure) error {
+	if cfg.PseudonymSalt == "" {
+		return fmt.Errorf("erasure requires erasure.pseudonym_salt")
+	}
+
+	switch cfg.Mode {
+	case "", ErasureModeAnonymize, ErasureModeDelete:
+	default:
+		return fmt.Errorf("erasure mode must be one of anonymize, delete")
+	}
+
+	return nil
+}
+
+func (s *erasureService) EraseUser(ctx context.Context, userID, mode, trigger string) (*models.ErasureReceipt, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user id is required")
+	}
+	if mode == "" {
+		mode = s.mode
+	}
+	if mode != ErasureModeAnonymize && mode != ErasureModeDelete {
+		return nil, fmt.Errorf("unknown erasure mode %q", mode)
+	}
+
+	pseudonym := s.pseudonym(userID)
+
+	// Logs under a legal hold are left untouched and reported on the receipt
+	holds, err := s.holds.Scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := s.receiptRepo.Get(ctx, pseudonym)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case receipt == nil:
+		receipt = &models.ErasureReceipt{Subject: pseudonym}
+		fallthrough
+	case receipt.Status != models.ErasureStatusInProgress:
+		receipt.Runs++
+		receipt.StartedAt = now
+		receipt.CompletedAt = time.Time{}
+		receipt.Checkpoint = primitive.NilObjectID
+	default:
+		fmt.Printf("Resuming erasure of %s after %s\n", pseudonym, receipt.Checkpoint.Hex())
+	}
+
+	receipt.Mode = mode
+	receipt.Status = models.ErasureStatusInProgress
+	receipt.Trigger = trigger
+	receipt.FieldPaths = s.fieldPaths
+	receipt.UpdatedAt = now
+	if err := s.receiptRepo.Save(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	// Each batch moves the checkpoint forward, so an interrupted run picks
+	// up where it stopped and a log that can't be rewritten is not retried
+	// forever
+	for {
+		if err := ctx.Err(); err != nil {
+			return receipt, err
+		}
+
+		logs, err := s.logRepo.FindReferencing(ctx, userID, s.fieldPaths, holds, receipt.Checkpoint, s.batchSize)
+		if err != nil {
+			return receipt, err
+		}
+		if len(logs) == 0 {
+			break
+		}
+
+		if err := s.eraseBatch(ctx, receipt, logs, userID, pseudonym); err != nil {
+			return receipt, err
+		}
+
+		receipt.Matched += int64(len(logs))
+		receipt.Checkpoint = logs[len(logs)-1].ID
+		receipt.UpdatedAt = time.Now()
+		if err := s.receiptRepo.Save(ctx, receipt); err != nil {
+			return receipt, err
+		}
+	}
+
+	receipt.Held, err = s.logRepo.CountHeld(ctx, userID, s.fieldPaths, holds)
+	if err != nil {
+		return receipt, err
+	}
+	LegalHeldTotal.Add(float64(receipt.Held), "erasure")
+
+	receipt.Status = models.ErasureStatusCompleted
+	receipt.Checkpoint = primitive.NilObjectID
+	receipt.CompletedAt = time.Now()
+	receipt.UpdatedAt = receipt.CompletedAt
+	if err := s.receiptRepo.Save(ctx, receipt); err != nil {
+		return receipt, err
+	}
+
+	fmt.Printf("Erasure of %s completed (%s): %d matched, %d anonymized, %d deleted, %d kept for legal hold\n",
+		pseudonym, mode, receipt.Matched, receipt.Anonymized, receipt.Deleted, receipt.Held)
+	return receipt, nil
+}
+
+func (s *erasureService) eraseBatch(ctx context.Context, receipt *models.ErasureReceipt, logs []models.ActivityLog, userID, pseudonym string) error {
+	// Tombstones let audit verify and inclusion proofs tell the erasure
+	// apart from tampering
+	tombstones := repo.TombstonesEnabled()
+	reference := "erasure:" + receipt.Subject
+
+	if receipt.Mode == ErasureModeDelete {
+		ids := make([]primitive.ObjectID, 0, len(logs))
+		var buried []models.ChainTombstone
+		for i := range logs {
+			ids = append(ids, logs[i].ID)
+			if tombstones {
+				contentHash, err := repo.ChainContentHash(&logs[i])
+				if err != nil {
+					return err
+				}
+				buried = append(buried, repo.NewChainTombstone(&logs[i], contentHash, models.TombstoneErased, reference))
+			}
+		}
+
+		if err := s.chainRepo.RecordTombstones(ctx, buried); err != nil {
+			return err
+		}
+		deleted, err := s.logRepo.DeleteByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		receipt.Deleted += deleted
+		return nil
+	}
+
+	changed := make([]models.ActivityLog, 0, len(logs))
+	var rewritten []models.ChainTombstone
+	for _, log := range logs {
+		var contentHash string
+		if tombstones {
+			var err error
+			if contentHash, err = repo.ChainContentHash(&log); err != nil {
+				return err
+			}
+		}
+
+		replaced := false
+		for _, path := range s.fieldPaths {
+			if replaceValue(log.Payload, strings.Split(path, "."), userID, pseudonym) {
+				replaced = true
+			}
+		}
+		if log.Subject == userID {
+			log.Subject = pseudonym
+			replaced = true
+		}
+		// Extensions and kept headers are not configured per path; any
+		// value equal to the user id is replaced
+		if replaceEverywhere(log.Extensions, userID, pseudonym) {
+			replaced = true
+		}
+		if replaceEverywhere(log.Metadata, userID, pseudonym) {
+			replaced = true
+		}
+		if !replaced {
+			continue
+		}
+		changed = append(changed, log)
+
+		if tombstones {
+			tombstone := repo.NewChainTombstone(&log, contentHash, models.TombstoneAnonymized, reference)
+			newContentHash, err := repo.ChainContentHash(&log)
+			if err != nil {
+				return err
+			}
+			tombstone.NewContentHash = newContentHash
+			rewritten = append(rewritten, tombstone)
+		}
+	}
+
+	if err := s.chainRepo.RecordTombstones(ctx, rewritten); err != nil {
+		return err
+	}
+	anonymized, err := s.logRepo.ReplaceUserFields(ctx, changed)
+	if err != nil {
+		return err
+	}
+	receipt.Anonymized += anonymized
+	return nil
+}
+
+// pseudonym is a stable stand-in for userID: the same user always gets the
+// same pseudonym, so anonymized logs can still be grouped by user
+func (s *erasureService) pseudonym(userID string) string {
+	mac := hmac.New(sha256.New, s.salt)
+	mac.Write([]byte(userID))
+	return "erased-" + hex.EncodeToString(mac.Sum(nil))[:24]
+}
+
+// replaceValue replaces from with to at path inside value, descending into
+// arrays along the way like MongoDB's dotted paths do. It reports whether
+// anything was replaced.
+func replaceValue(value interface{}, path []string, from, to string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return replaceInMap(v, path, from, to)
+	case primitive.M:
+		return replaceInMap(v, path, from, to)
+	case primitive.D:
+		replaced := false
+		for i := range v {
+			if v[i].Key != path[0] {
+				continue
+			}
+			if len(path) == 1 {
+				if next, ok := replaceLeaf(v[i].Value, from, to); ok {
+					v[i].Value = next
+					replaced = true
+				}
+			} else if replaceValue(v[i].Value, path[1:], from, to) {
+				replaced = true
+			}
+		}
+		return replaced
+	case primitive.A:
+		return replaceInArray(v, path, from, to)
+	case []interface{}:
+		return replaceInArray(v, path, from, to)
+	default:
+		return false
+	}
+}
+
+func replaceInMap(m map[string]interface{}, path []string, from, to string) bool {
+	current, exists := m[path[0]]
+	if !exists {
+		return false
+	}
+
+	if len(path) == 1 {
+		next, ok := replaceLeaf(current, from, to)
+		if ok {
+			m[path[0]] = next
+		}
+		return ok
+	}
+
+	return replaceValue(current, path[1:], from, to)
+}
+
+func replaceInArray(items []interface{}, path []string, from, to string) bool {
+	replaced := false
+	for _, item := range items {
+		if replaceValue(item, path, from, to) {
+			replaced = true
+		}
+	}
+	return replaced
+}
+
+// replaceLeaf replaces from with to in a leaf value that is either the
+// string itself or an array holding it
+func replaceLeaf(value interface{}, from, to string) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		if v == from {
+			return to, true
+		}
+	case primitive.A:
+		return v, replaceStrings(v, from, to)
+	case []interface{}:
+		return v, replaceStrings(v, from, to)
+	}
+	return value, false
+}
+
+// replaceEverywhere replaces from with to in every string inside value,
+// at any depth
+func replaceEverywhere(value interface{}, from, to string) bool {
+	replaced := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if s, ok := item.(string); ok && s == from {
+				v[key] = to
+				replaced = true
+			} else if replaceEverywhere(item, from, to) {
+				replaced = true
+			}
+		}
+	case primitive.M:
+		return replaceEverywhere(map[string]interface{}(v), from, to)
+	case primitive.D:
+		for i := range v {
+			if s, ok := v[i].Value.(string); ok && s == from {
+				v[i].Value = to
+				replaced = true
+			} else if replaceEverywhere(v[i].Value, from, to) {
+				replaced = true
+			}
+		}
+	case primitive.A:
+		return replaceEverywhere([]interface{}(v), from, to)
+	case []interface{}:
+		for i, item := range v {
+			if s, ok := item.(string); ok && s == from {
+				v[i] = to
+				replaced = true
+			} else if replaceEverywhere(item, from, to) {
+				replaced = true
+			}
+		}
+	}
+	return replaced
+}
+
+func replaceStrings(items []interface{}, from, to string) bool {
+	replaced := false
+	for i, item := range items {
+		if s, ok := item.(string); ok && s == from {
+			items[i] = to
+			replaced = true
+		}
+	}
+	return replaced
+}
@@ -3,8 +3,56 @@ package services
 import (
 	"context"
 	"event_service/internal/dto"
+	"event_service/internal/lease"
+	"event_service/internal/models"
+	"event_service/internal/repo"
+	"time"
 )
 
 type LogService interface {
 	ProcessEvent(ctx context.Context, event *dto.GenericEvent) error
 }
+
+type TimelineService interface {
+	// GetUserTimeline returns one page of the user's timeline; encrypted
+	// payload fields are decrypted only when decrypt is set
+	GetUserTimeline(ctx context.Context, userID string, topics []string, cursor string, limit int, decrypt bool) (*dto.TimelineResponse, error)
+}
+
+type ErasureService interface {
+	// EraseUser anonymizes or deletes every activity log referencing userID.
+	// An empty mode uses the configured one. Running it again for the same
+	// user resumes an interrupted run or picks up logs stored since.
+	EraseUser(ctx context.Context, userID, mode, trigger string) (*models.ErasureReceipt, error)
+}
+
+type LegalHoldService interface {
+	Create(ctx context.Context, hold *models.LegalHold) error
+	List(ctx context.Context, includeReleased bool) ([]models.LegalHold, error)
+	Release(ctx context.Context, id, releasedBy string) error
+	// Scope returns the active holds that destructive operations must skip
+	Scope(ctx context.Context) (repo.HoldScope, error)
+}
+
+type AuditService interface {
+	// VerifyChain walks the hash chain of partition, or of every partition
+	// when empty, and reports any break, deletion or modification
+	VerifyChain(ctx context.Context, partition string) ([]dto.ChainReport, error)
+}
+
+type CheckpointService interface {
+	// CreateCheckpoint signs the logs processed since the previous
+	// checkpoint, or in the last firstWindow when there is none, up to
+	// settle ago. It returns nil when the window is empty of time. Writes
+	// are checked against fence.
+	CreateCheckpoint(ctx context.Context, firstWindow, settle time.Duration, fence lease.Fence) (*models.AuditCheckpoint, error)
+	// Prove builds an inclusion proof of eventID against checkpointID, or
+	// against the checkpoint covering the log when empty
+	Prove(ctx context.Context, eventID, checkpointID string) (*dto.InclusionProof, error)
+}
+
+type QuarantineService interface {
+	// Quarantine keeps an event that failed source verification for review
+	// instead of storing it, once per event and handler
+	Quarantine(ctx context.Context, event *dto.GenericEvent, handler, routingKey, userID string, violations []models.SourceViolation) error
+}
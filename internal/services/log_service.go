@@ -5,23 +5,50 @@ import (
 	"fmt"
 	"time"
 
+	"event_service/global"
 	"event_service/internal/common"
 	"event_service/internal/dto"
+	"event_service/internal/encryption"
+	"event_service/internal/metrics"
 	"event_service/internal/models"
 	"event_service/internal/repo"
+	"event_service/internal/upcast"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var rollupErrorsTotal = metrics.NewCounter("event_service_rollup_errors_total",
+	"Activity logs stored without updating their daily rollup")
+
 type logService struct {
 	activityLogRepo repo.ActivityLogRepository
+	rollupRepo      repo.ActivityRollupRepository
 	transformer     *EventTransformer
+	redactor        *Redactor
+	encryptor       *encryption.Encryptor // nil when encryption is disabled
 }
 
 func NewLogService() LogService {
+	activityLogRepo := repo.NewActivityLogRepository()
+	if global.MongoBreaker != nil {
+		activityLogRepo = repo.NewCircuitBreakerActivityLogRepository(activityLogRepo, global.MongoBreaker)
+	}
+	if global.Spool != nil {
+		activityLogRepo = repo.NewSpoolingActivityLogRepository(activityLogRepo, global.Spool)
+	}
+
+	// The rules were validated when the configuration was loaded
+	redactor, err := NewRedactor(global.Config.Redaction)
+	if err != nil {
+		panic(fmt.Errorf("%w: %v", common.ErrConfigValidation, err))
+	}
+
 	return &logService{
-		activityLogRepo: repo.NewActivityLogRepository(),
+		activityLogRepo: activityLogRepo,
+		rollupRepo:      repo.NewActivityRollupRepository(),
 		transformer:     NewEventTransformer(),
+		redactor:        redactor,
+		encryptor:       encryption.Current(),
 	}
 }
 
@@ -36,23 +63,75 @@ func (s *logService) ProcessEvent(ctx context.Context, event *dto.GenericEvent)
 		return fmt.Errorf("failed to parse timestamp: %v", err)
 	}
 
+	// Upcast first so redaction and encryption rules see the current shape
+	payload, schemaVersion, err := upcast.Upcast(event.Topic, event.SchemaVersion, event.Payload)
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrEventValidation, err)
+	}
+	if schemaVersion > upcast.CurrentVersion(event.Topic) {
+		fmt.Printf("Event %s has schema version %d, newer than %s's current version; stored as is\n", event.EventID, schemaVersion, event.Topic)
+	}
+	event.Payload = payload
+
+	upcastFrom := max(event.SchemaVersion, 1)
+	if upcastFrom == schemaVersion {
+		upcastFrom = 0
+	}
+
+	// Redact before anything is written, including the spool
+	redactions := s.redactor.Redact(event)
+
 	activityLog := &models.ActivityLog{
 		ID:            primitive.NilObjectID,
 		EventID:       event.EventID,
 		Topic:         event.Topic,
 		SourceService: event.SourceService,
 		Timestamp:     timestamp,
+		Subject:       event.Subject,
 		Payload:       event.Payload,
+		Extensions:    event.Extensions,
+		Metadata:      event.Metadata,
+		SchemaVersion: schemaVersion,
+		UpcastFrom:    upcastFrom,
+		Redactions:    redactions,
+	}
+
+	if s.encryptor != nil {
+		if err := s.encryptor.Encrypt(ctx, activityLog); err != nil {
+			return fmt.Errorf("%w: %w", common.ErrEventProcessing, err)
+		}
 	}
 
 	if err := s.activityLogRepo.Create(ctx, activityLog); err != nil {
-		return fmt.Errorf("%w: %v", common.ErrEventProcessing, err)
+		// A redelivery of an event whose insert already committed, e.g. one
+		// requeued at the shutdown drain deadline
+		if repo.IsDuplicateEvent(err) {
+			fmt.Printf("Event %s is already stored, skipping\n", event.EventID)
+			return nil
+		}
+		return fmt.Errorf("%w: %w", common.ErrEventProcessing, err)
+	}
+
+	// Only a log that reached MongoDB has an ID; spooled logs are counted
+	// when the spool is replayed
+	if !activityLog.ID.IsZero() {
+		incrementRollup(ctx, s.rollupRepo, activityLog)
 	}
 
 	fmt.Printf("Event %s processed successfully and saved to database\n", event.EventID)
 	return nil
 }
 
+// incrementRollup counts a stored activity log in its daily rollup. A
+// failure only costs dashboard accuracy, so it is logged and counted rather
+// than failing the event; a backfill corrects the rollup.
+func incrementRollup(ctx context.Context, rollupRepo repo.ActivityRollupRepository, activityLog *models.ActivityLog) {
+	if err := rollupRepo.Increment(ctx, activityLog); err != nil {
+		rollupErrorsTotal.Inc()
+		fmt.Printf("Error updating rollup for event %s: %v\n", activityLog.EventID, err)
+	}
+}
+
 func (s *logService) parseTimestamp(timestampStr string) (time.Time, error) {
 	// Try different timestamp formats
 	formats := []string{
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"event_service/internal/dto"
+	"event_service/internal/metrics"
+	"event_service/pkg/setting"
+)
+
+const (
+	RedactionActionMask = "mask"
+	RedactionActionHash = "hash"
+	RedactionActionDrop = "drop"
+
+	redactionMask = "[REDACTED]"
+)
+
+var redactionsTotal = metrics.NewCounter("event_service_redactions_total",
+	"Payloads changed by a redaction rule", "rule")
+
+// Built-in detectors. They are heuristics: tune them with a custom pattern
+// when a topic carries look-alike values.
+var redactionDetectors = map[string]string{
+	"email": `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`,
+	"phone": `\+\d{8,15}\b|(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{2,4}\)[\s.\-]?|\b\d{2,4}[\s.\-])\d{3,4}[\s.\-]?\d{3,4}\b`,
+	"ipv4":  `\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`,
+	"ipv6":  `\b(?:[0-9A-Fa-f]{1,4}:){7}[0-9A-Fa-f]{1,4}\b|\b(?:[0-9A-Fa-f]{1,4}:){1,7}:(?:[0-9A-Fa-f]{1,4}(?::[0-9A-Fa-f]{1,4}){0,6})?`,
+}
+
+func init() {
+	redactionDetectors["ip"] = redactionDetectors["ipv4"] + "|" + redactionDetectors["ipv6"]
+}
+
+type redactionRule struct {
+	name    string
+	topics  []string
+	paths   [][]string
+	pattern *regexp.Regexp // nil for path-only rules
+	action  string
+}
+
+// Redactor removes PII from event payloads according to the redaction rules
+type Redactor struct {
+	rules   []redactionRule
+	hashKey []byte
+}
+
+// NewRedactor compiles the redaction rules; a disabled configuration yields
+// a redactor that changes nothing
+func NewRedactor(cfg setting.Redaction) (*Redactor, error) {
+	r := &Redactor{hashKey: []byte(cfg.HashKey)}
+	if !cfg.Enabled {
+		return r, nil
+	}
+
+	for i, rule := range cfg.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule_%d", i+1)
+		}
+
+		compiled := redactionRule{
+			name:   name,
+			topics: rule.Topics,
+			action: rule.Action,
+		}
+
+		switch rule.Action {
+		case RedactionActionMask, RedactionActionDrop:
+		case RedactionActionHash:
+			if cfg.HashKey == "" {
+				return nil, fmt.Errorf("redaction rule %s: hash action requires redaction.hash_key", name)
+			}
+		default:
+			return nil, fmt.Errorf("redaction rule %s: action must be one of mask, hash, drop", name)
+		}
+
+		for _, topic := range rule.Topics {
+			if _, err := path.Match(topic, ""); err != nil {
+				return nil, fmt.Errorf("redaction rule %s: invalid topic pattern %q", name, topic)
+			}
+		}
+
+		for _, p := range rule.Paths {
+			compiled.paths = append(compiled.paths, strings.Split(p, "."))
+		}
+
+		expression := rule.Pattern
+		if rule.Detector != "" {
+			if rule.Pattern != "" {
+				return nil, fmt.Errorf("redaction rule %s: set either detector or pattern", name)
+			}
+			var exists bool
+			if expression, exists = redactionDetectors[rule.Detector]; !exists {
+				return nil, fmt.Errorf("redaction rule %s: unknown detector %q", name, rule.Detector)
+			}
+		}
+		if expression != "" {
+			pattern, err := regexp.Compile(expression)
+			if err != nil {
+				return nil, fmt.Errorf("redaction rule %s: invalid pattern: %v", name, err)
+			}
+			compiled.pattern = pattern
+		}
+
+		if compiled.pattern == nil && len(compiled.paths) == 0 {
+			return nil, fmt.Errorf("redaction rule %s: paths, detector or pattern is required", name)
+		}
+
+		r.rules = append(r.rules, compiled)
+	}
+
+	return r, nil
+}
+
+// Redact applies the rules matching the event's topic to it in place and
+// returns the names of the rules that changed it. Paths address the
+// payload; rules without paths scan the payload and also the subject,
+// extensions and kept headers, which are stored with it.
+func (r *Redactor) Redact(event *dto.GenericEvent) []string {
+	var fired []string
+	payload := event.Payload
+
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !rule.appliesTo(event.Topic) {
+			continue
+		}
+
+		changed := false
+		if len(rule.paths) == 0 {
+			_, _, changed = r.detect(rule, payload)
+			if r.detectEnvelope(rule, event) {
+				changed = true
+			}
+		}
+		for _, segments := range rule.paths {
+			if r.applyAtPath(rule, payload, segments) {
+				changed = true
+			}
+		}
+
+		if changed {
+			fired = append(fired, rule.name)
+			redactionsTotal.Inc(rule.name)
+		}
+	}
+
+	return fired
+}
+
+// detectEnvelope runs a detector rule over the event fields stored next to
+// the payload. A dropped subject is left empty.
+func (r *Redactor) detectEnvelope(rule *redactionRule, event *dto.GenericEvent) bool {
+	changed := false
+
+	if event.Subject != "" {
+		out, keep, fired := r.detect(rule, event.Subject)
+		if fired {
+			changed = true
+			event.Subject = ""
+			if keep {
+				event.Subject = out.(string)
+			}
+		}
+	}
+
+	if _, _, fired := r.detect(rule, event.Extensions); fired {
+		changed = true
+	}
+
+	// filledFrom only names property sources; headers carry producer data
+	if headers, ok := event.Metadata["headers"].(map[string]interface{}); ok {
+		if _, _, fired := r.detect(rule, headers); fired {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func (rule *redactionRule) appliesTo(topic string) bool {
+	if len(rule.topics) == 0 {
+		return true
+	}
+	for _, pattern := range rule.topics {
+		if matched, _ := path.Match(pattern, topic); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// applyAtPath redacts what segments lead to inside value, descending into
+// arrays along the way
+func (r *Redactor) applyAtPath(rule *redactionRule, value interface{}, segments []string) bool {
+	changed := false
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range matchingKeys(v, segments[0]) {
+			if len(segments) > 1 {
+				if r.applyAtPath(rule, v[key], segments[1:]) {
+					changed = true
+				}
+				continue
+			}
+
+			out, keep, fired := r.redactLeaf(rule, v[key])
+			if !fired {
+				continue
+			}
+			changed = true
+			if keep {
+				v[key] = out
+			} else {
+				delete(v, key)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if r.applyAtPath(rule, item, segments) {
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+func matchingKeys(m map[string]interface{}, segment string) []string {
+	if segment != "*" {
+		if _, exists := m[segment]; exists {
+			return []string{segment}
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// redactLeaf redacts the value a path points at: all of it for path rules,
+// the detected text for detector rules. keep is false when it must be
+// removed.
+func (r *Redactor) redactLeaf(rule *redactionRule, value interface{}) (out interface{}, keep bool, fired bool) {
+	if rule.pattern != nil {
+		return r.detect(rule, value)
+	}
+
+	if items, ok := value.([]interface{}); ok {
+		return r.redactItems(items, func(item interface{}) (interface{}, bool, bool) {
+			return r.redactLeaf(rule, item)
+		})
+	}
+
+	if value == nil {
+		return value, true, false
+	}
+
+	switch rule.action {
+	case RedactionActionDrop:
+		return nil, false, true
+	case RedactionActionHash:
+		return r.hash(fmt.Sprint(value)), true, true
+	default:
+		return redactionMask, true, true
+	}
+}
+
+// detect redacts the text matched by the rule's pattern in every string
+// inside value
+func (r *Redactor) detect(rule *redactionRule, value interface{}) (out interface{}, keep bool, fired bool) {
+	switch v := value.(type) {
+	case string:
+		if !rule.pattern.MatchString(v) {
+			return v, true, false
+		}
+		switch rule.action {
+		case RedactionActionDrop:
+			return nil, false, true
+		case RedactionActionHash:
+			return rule.pattern.ReplaceAllStringFunc(v, r.hash), true, true
+		default:
+			return rule.pattern.ReplaceAllLiteralString(v, redactionMask), true, true
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			redacted, keepItem, firedItem := r.detect(rule, item)
+			if !firedItem {
+				continue
+			}
+			fired = true
+			if keepItem {
+				v[key] = redacted
+			} else {
+				delete(v, key)
+			}
+		}
+		return v, true, fired
+	case []interface{}:
+		return r.redactItems(v, func(item interface{}) (interface{}, bool, bool) {
+			return r.detect(rule, item)
+		})
+	default:
+		return value, true, false
+	}
+}
+
+// redactItems redacts each item of an array, leaving out dropped items
+func (r *Redactor) redactItems(items []interface{}, redact func(interface{}) (interface{}, bool, bool)) (interface{}, bool, bool) {
+	fired := false
+	kept := items[:0]
+	for _, item := range items {
+		out, keep, firedItem := redact(item)
+		if firedItem {
+			fired = true
+		}
+		if keep {
+			kept = append(kept, out)
+		}
+	}
+	return kept, true, fired
+}
+
+// hash is keyed so equal values stay joinable without being reversible by
+// anyone lacking the key
+func (r *Redactor) hash(value string) string {
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write([]byte(value))
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil))[:32]
+}
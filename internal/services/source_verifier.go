@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"event_service/internal/dto"
+	"event_service/internal/models"
+	"event_service/pkg/setting"
+)
+
+// SourceVerifier checks that an event's claimed topic and source service
+// agree with how it was delivered
+type SourceVerifier struct {
+	enabled                bool
+	routingKeyMatchesTopic bool
+	users                  map[string][]string // AMQP user-id -> allowed source services
+	topics                 []setting.TopicSources
+}
+
+// NewSourceVerifier validates the verification configuration; a disabled
+// configuration yields a verifier that accepts everything
+func NewSourceVerifier(cfg setting.Verification) (*SourceVerifier, error) {
+	v := &SourceVerifier{
+		enabled:                cfg.Enabled,
+		routingKeyMatchesTopic: cfg.RoutingKeyMatchesTopic,
+		topics:                 cfg.Topics,
+	}
+
+	for i, user := range cfg.Users {
+		if user.User == "" {
+			return nil, fmt.Errorf("verification user %d: user is required", i+1)
+		}
+		if v.users == nil {
+			v.users = make(map[string][]string)
+		}
+		v.users[user.User] = append(v.users[user.User], user.Sources...)
+	}
+
+	for i, rule := range cfg.Topics {
+		if len(rule.Topics) == 0 || len(rule.Sources) == 0 {
+			return nil, fmt.Errorf("verification topic rule %d: topics and sources are required", i+1)
+		}
+		for _, topic := range rule.Topics {
+			if _, err := path.Match(topic, ""); err != nil {
+				return nil, fmt.Errorf("verification topic rule %d: invalid topic pattern %q", i+1, topic)
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// Verify returns every check the event fails. routingKey and userID are
+// those of the original publish.
+func (v *SourceVerifier) Verify(event *dto.GenericEvent, routingKey, userID string) []models.SourceViolation {
+	if !v.enabled {
+		return nil
+	}
+
+	var violations []models.SourceViolation
+
+	if v.routingKeyMatchesTopic && routingKey != event.Topic {
+		violations = append(violations, models.SourceViolation{
+			Kind:   models.ViolationRoutingKey,
+			Detail: fmt.Sprintf("routing key %q does not match topic %q", routingKey, event.Topic),
+		})
+	}
+
+	if v.users != nil {
+		sources, known := v.users[userID]
+		switch {
+		case userID == "":
+			violations = append(violations, models.SourceViolation{
+				Kind:   models.ViolationUser,
+				Detail: "message has no user-id",
+			})
+		case !known:
+			violations = append(violations, models.SourceViolation{
+				Kind:   models.ViolationUser,
+				Detail: fmt.Sprintf("user %q is not allowed to publish events", userID),
+			})
+		case !slices.Contains(sources, event.SourceService):
+			violations = append(violations, models.SourceViolation{
+				Kind:   models.ViolationUser,
+				Detail: fmt.Sprintf("user %q may not publish as %q", userID, event.SourceService),
+			})
+		}
+	}
+
+	if allowed, restricted := v.allowedSources(event.Topic); restricted && !slices.Contains(allowed, event.SourceService) {
+		violations = append(violations, models.SourceViolation{
+			Kind:   models.ViolationTopicSource,
+			Detail: fmt.Sprintf("%q may not publish %q", event.SourceService, event.Topic),
+		})
+	}
+
+	return violations
+}
+
+// allowedSources returns the sources of every rule matching topic, and
+// whether any rule matched
+func (v *SourceVerifier) allowedSources(topic string) ([]string, bool) {
+	var allowed []string
+	restricted := false
+	for _, rule := range v.topics {
+		for _, pattern := range rule.Topics {
+			if matched, _ := path.Match(pattern, topic); matched {
+				allowed = append(allowed, rule.Sources...)
+				restricted = true
+				break
+			}
+		}
+	}
+	return allowed, restricted
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/metrics"
+	"event_service/internal/models"
+	"event_service/internal/repo"
+	"event_service/internal/spool"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	spoolReplayedTotal = metrics.NewCounter("event_service_spool_replayed_total",
+		"Spooled activity logs replayed into MongoDB")
+	spoolReplayDroppedTotal = metrics.NewCounter("event_service_spool_replay_dropped_total",
+		"Spooled activity logs dropped during replay because MongoDB rejected them")
+)
+
+// SpoolDrainer periodically replays spooled activity logs into MongoDB once
+// it is reachable again
+type SpoolDrainer struct {
+	spool           *spool.Spool
+	activityLogRepo repo.ActivityLogRepository
+	rollupRepo      repo.ActivityRollupRepository
+	interval        time.Duration
+	stopChannel     chan struct{}
+	done            chan struct{}
+}
+
+func NewSpoolDrainer(s *spool.Spool, interval time.Duration) *SpoolDrainer {
+	return &SpoolDrainer{
+		spool:           s,
+		activityLogRepo: repo.NewActivityLogRepository(),
+		rollupRepo:      repo.NewActivityRollupRepository(),
+		interval:        interval,
+		stopChannel:     make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+}
+
+func (d *SpoolDrainer) Start(ctx context.Context) {
+	go d.run(ctx)
+}
+
+// Stop waits for an ongoing replay round to finish
+func (d *SpoolDrainer) Stop() {
+	close(d.stopChannel)
+	<-d.done
+}
+
+func (d *SpoolDrainer) run(ctx context.Context) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stopChannel:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.drainOnce(ctx)
+		}
+	}
+}
+
+func (d *SpoolDrainer) drainOnce(ctx context.Context) {
+	records, _ := d.spool.Depth()
+	if records == 0 {
+		return
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	err := global.MongoClient.Ping(pingCtx, nil)
+	cancel()
+	if err != nil {
+		fmt.Printf("MongoDB still unavailable, %d spooled events waiting: %v\n", records, err)
+		return
+	}
+
+	fmt.Printf("Replaying %d spooled events into MongoDB...\n", records)
+
+	replayed, err := d.spool.Replay(func(data []byte) error {
+		return d.replayRecord(ctx, data)
+	})
+	if err != nil {
+		fmt.Printf("Spool replay paused after %d events: %v\n", replayed, err)
+		return
+	}
+
+	fmt.Printf("Spool replay completed: %d events replayed\n", replayed)
+}
+
+func (d *SpoolDrainer) replayRecord(ctx context.Context, data []byte) error {
+	var activityLog models.ActivityLog
+	if err := bson.Unmarshal(data, &activityLog); err != nil {
+		spoolReplayDroppedTotal.Inc()
+		fmt.Printf("Dropping undecodable spool record: %v\n", err)
+		return nil
+	}
+
+	insertCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := d.activityLogRepo.Create(insertCtx, &activityLog)
+	switch {
+	case err == nil:
+		spoolReplayedTotal.Inc()
+		incrementRollup(insertCtx, d.rollupRepo, &activityLog)
+		return nil
+	case repo.IsDuplicateEvent(err):
+		// Already stored by an earlier, interrupted replay
+		spoolReplayedTotal.Inc()
+		return nil
+	case repo.IsConnectivityError(err):
+		return err
+	default:
+		spoolReplayDroppedTotal.Inc()
+		fmt.Printf("Dropping spooled event %s rejected by MongoDB: %v\n", activityLog.EventID, err)
+		return nil
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"event_service/global"
+	"event_service/internal/common"
+	"event_service/internal/dto"
+	"event_service/internal/encryption"
+	"event_service/internal/models"
+	"event_service/internal/repo"
+	"event_service/internal/upcast"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	TimelineRoleActor   = "actor"
+	TimelineRoleSubject = "subject"
+
+	DefaultTimelineLimit = 50
+	MaxTimelineLimit     = 200
+)
+
+var (
+	// Payload fields naming the user who performed the action
+	timelineActorFields = []string{"createdById", "actorId"}
+	// Payload fields naming the user the action was performed on
+	timelineSubjectFields = []string{"userId", "memberId"}
+)
+
+type timelineService struct {
+	queryRepo    repo.ActivityLogQueryRepository
+	encryptor    *encryption.Encryptor // nil when encryption is disabled
+	upcastOnRead bool
+}
+
+func NewTimelineService() TimelineService {
+	return &timelineService{
+		queryRepo:    repo.NewActivityLogQueryRepository(),
+		encryptor:    encryption.Current(),
+		upcastOnRead: global.Config.Schema.UpcastOnRead,
+	}
+}
+
+func (s *timelineService) GetUserTimeline(ctx context.Context, userID string, topics []string, cursor string, limit int, decrypt bool) (*dto.TimelineResponse, error) {
+	if limit <= 0 {
+		limit = DefaultTimelineLimit
+	}
+	if limit > MaxTimelineLimit {
+		limit = MaxTimelineLimit
+	}
+
+	query := repo.TimelineQuery{
+		UserID:        userID,
+		ActorFields:   timelineActorFields,
+		SubjectFields: timelineSubjectFields,
+		Topics:        topics,
+		Limit:         limit + 1, // one extra to know whether there is a next page
+	}
+
+	if cursor != "" {
+		timestamp, id, err := decodeTimelineCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.BeforeTimestamp = timestamp
+		query.BeforeID = id
+	}
+
+	logs, err := s.queryRepo.UserTimeline(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TimelineResponse{
+		UserID:  userID,
+		Entries: make([]dto.TimelineEntry, 0, len(logs)),
+	}
+
+	if len(logs) > limit {
+		logs = logs[:limit]
+		last := logs[len(logs)-1]
+		response.NextCursor = encodeTimelineCursor(last.Timestamp, last.ID)
+	}
+
+	for _, log := range logs {
+		// Without decryption, encrypted fields are returned as ciphertext
+		if decrypt && s.encryptor != nil {
+			if err := s.encryptor.Decrypt(ctx, &log); err != nil {
+				return nil, err
+			}
+		}
+
+		if s.upcastOnRead {
+			s.upcast(&log)
+		}
+
+		response.Entries = append(response.Entries, dto.TimelineEntry{
+			EventID:       log.EventID,
+			Topic:         log.Topic,
+			SourceService: log.SourceService,
+			Timestamp:     log.Timestamp,
+			SchemaVersion: max(log.SchemaVersion, 1),
+			Roles:         timelineRoles(&log, userID),
+			Payload:       log.Payload,
+		})
+	}
+
+	return response, nil
+}
+
+// upcast migrates a stored payload to the current shape of its topic. The
+// stored log is left as is. Encrypted payloads are skipped: moving a
+// ciphertext to another path would stop it from decrypting.
+func (s *timelineService) upcast(log *models.ActivityLog) {
+	if log.Encryption != nil {
+		return
+	}
+
+	payload, schemaVersion, err := upcast.Upcast(log.Topic, log.SchemaVersion, log.Payload)
+	if err != nil {
+		fmt.Printf("Returning event %s in its stored shape: %v\n", log.EventID, err)
+		return
+	}
+
+	log.Payload = payload
+	log.SchemaVersion = schemaVersion
+}
+
+func timelineRoles(log *models.ActivityLog, userID string) []string {
+	var roles []string
+	if payloadHasValue(log.Payload, timelineActorFields, userID) {
+		roles = append(roles, TimelineRoleActor)
+	}
+	if payloadHasValue(log.Payload, timelineSubjectFields, userID) {
+		roles = append(roles, TimelineRoleSubject)
+	}
+	return roles
+}
+
+func payloadHasValue(payload map[string]interface{}, fields []string, value string) bool {
+	for _, field := range fields {
+		if v, ok := payload[field].(string); ok && v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// The cursor is the (timestamp, _id) of the last entry of the previous page
+func encodeTimelineCursor(timestamp time.Time, id primitive.ObjectID) string {
+	raw := timestamp.UTC().Format(time.RFC3339Nano) + "|" + id.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTimelineCursor(cursor string) (time.Time, primitive.ObjectID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, fmt.Errorf("%w: %v", common.ErrInvalidCursor, err)
+	}
+
+	timestampPart, idPart, found := strings.Cut(string(raw), "|")
+	if !found {
+		return time.Time{}, primitive.NilObjectID, common.ErrInvalidCursor
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, timestampPart)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, fmt.Errorf("%w: %v", common.ErrInvalidCursor, err)
+	}
+
+	id, err := primitive.ObjectIDFromHex(idPart)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, fmt.Errorf("%w: %v", common.ErrInvalidCursor, err)
+	}
+
+	return timestamp, id, nil
+}
@@ -0,0 +1,451 @@
+package spool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"event_service/internal/common"
+)
+
+// Fsync policies
+const (
+	FsyncAlways   = "always"
+	FsyncInterval = "interval"
+	FsyncNever    = "never"
+)
+
+const (
+	segmentPrefix    = "segment-"
+	segmentSuffix    = ".log"
+	corruptSuffix    = ".corrupt"
+	recordHeaderSize = 8 // uint32 length + uint32 CRC32
+	maxRecordBytes   = 16 << 20
+)
+
+type Options struct {
+	Directory       string
+	SegmentMaxBytes int64
+	MaxTotalBytes   int64
+	FsyncPolicy     string
+	FsyncInterval   time.Duration
+}
+
+type segment struct {
+	seq             uint64
+	path            string
+	bytes           int64
+	records         int
+	replayedOffset  int64
+	replayedRecords int
+}
+
+// Spool is a durable write-ahead buffer made of append-only segment files.
+// Records are appended to the active segment, which is sealed when it reaches
+// the size limit or when a replay starts; sealed segments are replayed oldest
+// first and deleted once every record in them has been replayed.
+type Spool struct {
+	opts Options
+
+	mu           sync.Mutex
+	sealed       []*segment
+	active       *segment
+	file         *os.File
+	dirty        bool
+	closed       bool
+	nextSeq      uint64
+	totalBytes   int64
+	totalRecords int
+
+	stopSync chan struct{}
+	syncDone chan struct{}
+}
+
+// Open recovers any segments left in the directory and prepares the spool
+// for appends. Torn records at the tail of a segment (e.g. after a crash
+// mid-write) are truncated away.
+func Open(opts Options) (*Spool, error) {
+	if err := os.MkdirAll(opts.Directory, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %v", err)
+	}
+
+	s := &Spool{
+		opts:    opts,
+		nextSeq: 1,
+	}
+
+	if err := s.recover(); err != nil {
+		return nil, err
+	}
+
+	if opts.FsyncPolicy == FsyncInterval && opts.FsyncInterval > 0 {
+		s.stopSync = make(chan struct{})
+		s.syncDone = make(chan struct{})
+		go s.syncLoop()
+	}
+
+	return s, nil
+}
+
+func (s *Spool) recover() error {
+	entries, err := os.ReadDir(s.opts.Directory)
+	if err != nil {
+		return fmt.Errorf("failed to read spool directory: %v", err)
+	}
+
+	var seqs []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		seq, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	for _, seq := range seqs {
+		seg := &segment{seq: seq, path: s.segmentPath(seq)}
+
+		records, validBytes, err := scanSegment(seg.path)
+		if err != nil {
+			fmt.Printf("Spool segment %s is unreadable, moving it aside: %v\n", seg.path, err)
+			os.Rename(seg.path, seg.path+corruptSuffix)
+			continue
+		}
+
+		info, err := os.Stat(seg.path)
+		if err == nil && info.Size() > validBytes {
+			fmt.Printf("Truncating torn tail of spool segment %s (%d bytes)\n", seg.path, info.Size()-validBytes)
+			if err := os.Truncate(seg.path, validBytes); err != nil {
+				return fmt.Errorf("failed to truncate spool segment %s: %v", seg.path, err)
+			}
+		}
+
+		if records == 0 {
+			os.Remove(seg.path)
+			continue
+		}
+
+		seg.bytes = validBytes
+		seg.records = records
+		s.sealed = append(s.sealed, seg)
+		s.totalBytes += validBytes
+		s.totalRecords += records
+		s.nextSeq = seq + 1
+	}
+
+	if s.totalRecords > 0 {
+		fmt.Printf("Recovered %d spooled records in %d segments\n", s.totalRecords, len(s.sealed))
+	}
+
+	return nil
+}
+
+// Append durably stores a record. It returns common.ErrSpoolFull when the
+// total size limit would be exceeded.
+func (s *Spool) Append(data []byte) error {
+	size := int64(recordHeaderSize + len(data))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("%w: spool is closed", common.ErrSpoolWrite)
+	}
+
+	if s.opts.MaxTotalBytes > 0 && s.totalBytes+size > s.opts.MaxTotalBytes {
+		return common.ErrSpoolFull
+	}
+
+	if s.active != nil && s.opts.SegmentMaxBytes > 0 && s.active.bytes+size > s.opts.SegmentMaxBytes {
+		if err := s.sealActive(); err != nil {
+			return err
+		}
+	}
+
+	if s.active == nil {
+		if err := s.openActive(); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, size)
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(data)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(data))
+	copy(buf[recordHeaderSize:], data)
+
+	if _, err := s.file.Write(buf); err != nil {
+		// Drop whatever part of the record made it to disk so the next
+		// append does not land after a torn record
+		s.file.Truncate(s.active.bytes)
+		return fmt.Errorf("%w: %v", common.ErrSpoolWrite, err)
+	}
+
+	if s.opts.FsyncPolicy == FsyncAlways {
+		if err := s.file.Sync(); err != nil {
+			return fmt.Errorf("%w: fsync failed: %v", common.ErrSpoolWrite, err)
+		}
+	} else {
+		s.dirty = true
+	}
+
+	s.active.bytes += size
+	s.active.records++
+	s.totalBytes += size
+	s.totalRecords++
+
+	return nil
+}
+
+// Replay feeds every spooled record, oldest first, to fn. It stops at the
+// first error returned by fn; records already replayed are not replayed
+// again by later calls within the same process.
+func (s *Spool) Replay(fn func(data []byte) error) (int, error) {
+	s.mu.Lock()
+	if s.active != nil && s.active.records > 0 {
+		if err := s.sealActive(); err != nil {
+			s.mu.Unlock()
+			return 0, err
+		}
+	}
+	segments := make([]*segment, len(s.sealed))
+	copy(segments, s.sealed)
+	s.mu.Unlock()
+
+	replayed := 0
+	for _, seg := range segments {
+		n, err := s.replaySegment(seg, fn)
+		replayed += n
+
+		if errors.Is(err, common.ErrSpoolCorrupt) {
+			fmt.Printf("Spool segment %s is corrupt, moving it aside: %v\n", seg.path, err)
+			os.Rename(seg.path, seg.path+corruptSuffix)
+			s.dropSegment(seg)
+			continue
+		}
+		if err != nil {
+			return replayed, err
+		}
+
+		os.Remove(seg.path)
+		s.dropSegment(seg)
+	}
+
+	return replayed, nil
+}
+
+func (s *Spool) replaySegment(seg *segment, fn func(data []byte) error) (int, error) {
+	file, err := os.Open(seg.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open spool segment %s: %v", seg.path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(seg.replayedOffset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to seek spool segment %s: %v", seg.path, err)
+	}
+
+	reader := bufio.NewReader(file)
+	replayed := 0
+	for {
+		data, err := readRecord(reader)
+		if err == io.EOF {
+			return replayed, nil
+		}
+		if err != nil {
+			return replayed, fmt.Errorf("%w: %s: %v", common.ErrSpoolCorrupt, seg.path, err)
+		}
+
+		if err := fn(data); err != nil {
+			return replayed, err
+		}
+
+		size := int64(recordHeaderSize + len(data))
+		s.mu.Lock()
+		seg.replayedOffset += size
+		seg.replayedRecords++
+		s.totalBytes -= size
+		s.totalRecords--
+		s.mu.Unlock()
+		replayed++
+	}
+}
+
+// Depth returns the number of records and bytes waiting to be replayed
+func (s *Spool) Depth() (int, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.totalRecords, s.totalBytes
+}
+
+// Close flushes and closes the active segment
+func (s *Spool) Close() error {
+	if s.stopSync != nil {
+		close(s.stopSync)
+		<-s.syncDone
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if s.active != nil {
+		return s.sealActive()
+	}
+
+	return nil
+}
+
+func (s *Spool) openActive() error {
+	seq := s.nextSeq
+	path := s.segmentPath(seq)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrSpoolWrite, err)
+	}
+
+	s.nextSeq++
+	s.file = file
+	s.active = &segment{seq: seq, path: path}
+	return nil
+}
+
+// sealActive must be called with s.mu held
+func (s *Spool) sealActive() error {
+	if s.active == nil {
+		return nil
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("%w: fsync failed: %v", common.ErrSpoolWrite, err)
+	}
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrSpoolWrite, err)
+	}
+
+	if s.active.records > 0 {
+		s.sealed = append(s.sealed, s.active)
+	} else {
+		os.Remove(s.active.path)
+	}
+
+	s.active = nil
+	s.file = nil
+	s.dirty = false
+	return nil
+}
+
+func (s *Spool) dropSegment(seg *segment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, candidate := range s.sealed {
+		if candidate == seg {
+			s.sealed = append(s.sealed[:i], s.sealed[i+1:]...)
+			break
+		}
+	}
+
+	// Non-zero only for corrupt segments that were dropped part way through
+	s.totalBytes -= seg.bytes - seg.replayedOffset
+	s.totalRecords -= seg.records - seg.replayedRecords
+}
+
+func (s *Spool) syncLoop() {
+	defer close(s.syncDone)
+
+	ticker := time.NewTicker(s.opts.FsyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopSync:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if s.dirty && s.file != nil {
+				if err := s.file.Sync(); err != nil {
+					fmt.Printf("Error syncing spool segment: %v\n", err)
+				} else {
+					s.dirty = false
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *Spool) segmentPath(seq uint64) string {
+	return filepath.Join(s.opts.Directory, fmt.Sprintf("%s%020d%s", segmentPrefix, seq, segmentSuffix))
+}
+
+// scanSegment counts the intact records of a segment and returns the offset
+// right after the last one
+func scanSegment(path string) (int, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	records := 0
+	var validBytes int64
+	for {
+		data, err := readRecord(reader)
+		if err != nil {
+			// io.EOF is a clean end; anything else is a torn or corrupt tail
+			return records, validBytes, nil
+		}
+		records++
+		validBytes += int64(recordHeaderSize + len(data))
+	}
+}
+
+func readRecord(reader *bufio.Reader) ([]byte, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("torn record header")
+		}
+		return nil, err
+	}
+
+	length := binary.LittleEndian.Uint32(header[0:4])
+	checksum := binary.LittleEndian.Uint32(header[4:8])
+	if length > maxRecordBytes {
+		return nil, fmt.Errorf("record length %d exceeds limit", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, fmt.Errorf("torn record body: %v", err)
+	}
+
+	if crc32.ChecksumIEEE(data) != checksum {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+
+	return data, nil
+}
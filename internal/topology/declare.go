@@ -0,0 +1,61 @@
+package topology
+
+import (
+	"fmt"
+
+	"event_service/pkg/setting"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Declare idempotently declares every exchange, queue and binding of the
+// topology. Declaring an object that already exists with different
+// properties fails with a PRECONDITION_FAILED channel error, so the channel
+// should be dedicated to the declaration.
+func Declare(ch *amqp091.Channel, t setting.Topology) error {
+	for _, e := range t.Exchanges {
+		err := ch.ExchangeDeclare(
+			e.Name,       // name
+			e.Type,       // type
+			e.Durable,    // durable
+			e.AutoDelete, // auto-deleted
+			false,        // internal
+			false,        // no-wait
+			nil,          // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", e.Name, err)
+		}
+	}
+
+	for _, q := range t.Queues {
+		_, err := ch.QueueDeclare(
+			q.Name,            // name
+			q.Durable,         // durable
+			q.AutoDelete,      // delete when unused
+			false,             // exclusive
+			false,             // no-wait
+			QueueArguments(q), // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", q.Name, err)
+		}
+	}
+
+	for _, b := range t.Bindings {
+		err := ch.QueueBind(
+			b.Queue,      // queue name
+			b.RoutingKey, // routing key
+			b.Exchange,   // exchange
+			false,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to bind queue %s to %s (%s): %w", b.Queue, b.Exchange, b.RoutingKey, err)
+		}
+	}
+
+	fmt.Printf("Topology declared: %d exchanges, %d queues, %d bindings\n",
+		len(t.Exchanges), len(t.Queues), len(t.Bindings))
+	return nil
+}
@@ -0,0 +1,130 @@
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// errNotFound is returned by the management client for 404 responses
+var errNotFound = fmt.Errorf("not found")
+
+// ManagementClient is a minimal client for the RabbitMQ management HTTP API
+type ManagementClient struct {
+	baseURL  string
+	vhost    string
+	user     string
+	password string
+	http     *http.Client
+}
+
+func NewManagementClient(baseURL, vhost, user, password string) *ManagementClient {
+	if vhost == "" {
+		vhost = "/"
+	}
+
+	return &ManagementClient{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		vhost:    vhost,
+		user:     user,
+		password: password,
+		http:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type managementExchange struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Durable    bool   `json:"durable"`
+	AutoDelete bool   `json:"auto_delete"`
+}
+
+type managementQueue struct {
+	Name                    string                 `json:"name"`
+	Durable                 bool                   `json:"durable"`
+	AutoDelete              bool                   `json:"auto_delete"`
+	Arguments               map[string]interface{} `json:"arguments"`
+	SingleActiveConsumerTag string                 `json:"single_active_consumer_tag"`
+	Messages                int                    `json:"messages"`
+}
+
+type managementBinding struct {
+	Source          string `json:"source"`
+	Destination     string `json:"destination"`
+	DestinationType string `json:"destination_type"`
+	RoutingKey      string `json:"routing_key"`
+}
+
+func (c *ManagementClient) exchange(ctx context.Context, name string) (*managementExchange, error) {
+	var result managementExchange
+	if err := c.get(ctx, "/api/exchanges/"+c.escapedVhost()+"/"+url.PathEscape(name), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *ManagementClient) queue(ctx context.Context, name string) (*managementQueue, error) {
+	var result managementQueue
+	if err := c.get(ctx, "/api/queues/"+c.escapedVhost()+"/"+url.PathEscape(name), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SingleActiveConsumerTag returns the tag of the consumer currently
+// receiving deliveries from a single-active-consumer queue, or "" if none
+func (c *ManagementClient) SingleActiveConsumerTag(ctx context.Context, queue string) (string, error) {
+	q, err := c.queue(ctx, queue)
+	if err != nil {
+		return "", err
+	}
+	return q.SingleActiveConsumerTag, nil
+}
+
+// QueueDepth returns the number of messages in a queue
+func (c *ManagementClient) QueueDepth(ctx context.Context, queue string) (int, error) {
+	q, err := c.queue(ctx, queue)
+	if err != nil {
+		return 0, err
+	}
+	return q.Messages, nil
+}
+
+func (c *ManagementClient) queueBindings(ctx context.Context, queue string) ([]managementBinding, error) {
+	var result []managementBinding
+	if err := c.get(ctx, "/api/queues/"+c.escapedVhost()+"/"+url.PathEscape(queue)+"/bindings", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *ManagementClient) escapedVhost() string {
+	return url.PathEscape(c.vhost)
+}
+
+func (c *ManagementClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.user, c.password)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("management API request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("management API %s returned %s", path, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
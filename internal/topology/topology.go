@@ -0,0 +1,181 @@
+package topology
+
+import (
+	"sort"
+
+	"event_service/pkg/setting"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Resolve returns the effective topology: the configured objects plus the
+// queues and bindings implied by the event sources and consumers. A queue listed in the
+// topology section takes precedence over the one derived from a source, so
+// operators can add arguments (DLX, TTL, queue type...) to source queues.
+func Resolve(rabbitCfg setting.RabbitMQ, topologyCfg setting.Topology) setting.Topology {
+	result := setting.Topology{
+		Exchanges: append([]setting.TopologyExchange(nil), topologyCfg.Exchanges...),
+		Queues:    append([]setting.TopologyQueue(nil), topologyCfg.Queues...),
+		Bindings:  append([]setting.TopologyBinding(nil), topologyCfg.Bindings...),
+	}
+
+	queues := make(map[string]bool)
+	for _, q := range result.Queues {
+		queues[q.Name] = true
+	}
+	bindings := make(map[setting.TopologyBinding]bool)
+	for _, b := range result.Bindings {
+		bindings[b] = true
+	}
+
+	names := make([]string, 0, len(rabbitCfg.Sources))
+	for name := range rabbitCfg.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		source := rabbitCfg.Sources[name]
+
+		for _, queue := range []string{source.Queue, source.DeadLetterQueue} {
+			if queue == "" || queues[queue] {
+				continue
+			}
+			result.Queues = append(result.Queues, setting.TopologyQueue{Name: queue, Durable: true})
+			queues[queue] = true
+		}
+
+		binding := setting.TopologyBinding{
+			Exchange:   source.Exchange,
+			Queue:      source.Queue,
+			RoutingKey: source.BindingKey,
+		}
+		if !bindings[binding] {
+			result.Bindings = append(result.Bindings, binding)
+			bindings[binding] = true
+		}
+	}
+
+	names = names[:0]
+	for name := range rabbitCfg.Consumers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Consumers reading a queue of their own (not one of a source) still
+	// need it and its dead letter queue declared, and every consumer that
+	// retries by republishing needs its retry queue. A consumer's queue type,
+	// delivery limit and single-active-consumer flag apply to derived queues only; a queue listed in the
+	// topology section is declared exactly as configured.
+	for _, name := range names {
+		consumer := rabbitCfg.Consumers[name]
+
+		for _, queue := range []string{consumer.Queue, consumer.DeadLetterQueue} {
+			if queue == "" || queues[queue] {
+				continue
+			}
+			result.Queues = append(result.Queues, setting.TopologyQueue{Name: queue, Durable: true})
+			queues[queue] = true
+		}
+
+		for i := range result.Queues {
+			q := &result.Queues[i]
+			if q.Name != consumer.Queue || i < len(topologyCfg.Queues) {
+				continue
+			}
+
+			q.QueueType = consumer.QueueType
+			q.SingleActiveConsumer = consumer.SingleActiveConsumer
+			if consumer.QueueType == "quorum" && consumer.DeliveryLimit > 0 {
+				// Messages over the limit are dead-lettered by the broker
+				q.DeliveryLimit = consumer.DeliveryLimit
+				q.DeadLetterRoutingKey = consumer.DeadLetterQueue
+			}
+		}
+
+		// Retries wait out their delay in the retry queue, which
+		// dead-letters them back to the consumer's queue through the default
+		// exchange once the TTL expires. It has no consumers of its own.
+		if consumer.RetryQueue != "" && !queues[consumer.RetryQueue] {
+			result.Queues = append(result.Queues, setting.TopologyQueue{
+				Name:                 consumer.RetryQueue,
+				Durable:              true,
+				MessageTTLMs:         retryTTLMs(consumer.RetryDelaySeconds),
+				DeadLetterRoutingKey: consumer.Queue,
+			})
+			queues[consumer.RetryQueue] = true
+		}
+	}
+
+	return result
+}
+
+// retryTTLMs is the x-message-ttl of a retry queue. A zero TTL would not be
+// declared at all and leave retries parked forever, so the shortest delay is
+// one millisecond.
+func retryTTLMs(delaySeconds int) int {
+	if delaySeconds <= 0 {
+		return 1
+	}
+	return delaySeconds * 1000
+}
+
+// QueueArguments builds the x-arguments a queue is declared with
+func QueueArguments(q setting.TopologyQueue) amqp091.Table {
+	args := amqp091.Table{}
+
+	for key, value := range q.Arguments {
+		args[key] = normalizeArgument(value)
+	}
+
+	if q.QueueType != "" {
+		args["x-queue-type"] = q.QueueType
+	}
+	if q.DeadLetterExchange != "" || q.DeadLetterRoutingKey != "" {
+		// An empty exchange with a routing key dead-letters straight to the
+		// named queue through the default exchange
+		args["x-dead-letter-exchange"] = q.DeadLetterExchange
+	}
+	if q.DeadLetterRoutingKey != "" {
+		args["x-dead-letter-routing-key"] = q.DeadLetterRoutingKey
+	}
+	if q.DeliveryLimit > 0 {
+		args["x-delivery-limit"] = int64(q.DeliveryLimit)
+	}
+	if q.SingleActiveConsumer {
+		args["x-single-active-consumer"] = true
+	}
+	if q.MessageTTLMs > 0 {
+		args["x-message-ttl"] = int64(q.MessageTTLMs)
+	}
+	if q.MaxLength > 0 {
+		args["x-max-length"] = int64(q.MaxLength)
+	}
+	if q.MaxLengthBytes > 0 {
+		args["x-max-length-bytes"] = q.MaxLengthBytes
+	}
+	if q.Overflow != "" {
+		args["x-overflow"] = q.Overflow
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+// normalizeArgument converts values decoded from YAML into types the AMQP
+// table encoder accepts
+func normalizeArgument(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
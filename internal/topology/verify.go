@@ -0,0 +1,157 @@
+package topology
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"event_service/pkg/setting"
+)
+
+// Drift is a difference between the configured topology and the broker
+type Drift struct {
+	Kind   string // exchange, queue, binding
+	Name   string
+	Reason string
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s %s: %s", d.Kind, d.Name, d.Reason)
+}
+
+// Verify compares the topology with what the broker reports through the
+// management API and returns every difference found. Bindings on configured
+// queues that are not in the topology are reported as well.
+func Verify(ctx context.Context, client *ManagementClient, t setting.Topology) ([]Drift, error) {
+	var drifts []Drift
+
+	for _, e := range t.Exchanges {
+		actual, err := client.exchange(ctx, e.Name)
+		if errors.Is(err, errNotFound) {
+			drifts = append(drifts, Drift{"exchange", e.Name, "missing"})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if actual.Type != e.Type {
+			drifts = append(drifts, Drift{"exchange", e.Name, fmt.Sprintf("type is %s, expected %s", actual.Type, e.Type)})
+		}
+		if actual.Durable != e.Durable {
+			drifts = append(drifts, Drift{"exchange", e.Name, fmt.Sprintf("durable is %t, expected %t", actual.Durable, e.Durable)})
+		}
+		if actual.AutoDelete != e.AutoDelete {
+			drifts = append(drifts, Drift{"exchange", e.Name, fmt.Sprintf("auto_delete is %t, expected %t", actual.AutoDelete, e.AutoDelete)})
+		}
+	}
+
+	existingQueues := make(map[string]bool)
+	for _, q := range t.Queues {
+		actual, err := client.queue(ctx, q.Name)
+		if errors.Is(err, errNotFound) {
+			drifts = append(drifts, Drift{"queue", q.Name, "missing"})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		existingQueues[q.Name] = true
+
+		if actual.Durable != q.Durable {
+			drifts = append(drifts, Drift{"queue", q.Name, fmt.Sprintf("durable is %t, expected %t", actual.Durable, q.Durable)})
+		}
+		drifts = append(drifts, compareArguments(q.Name, QueueArguments(q), actual.Arguments)...)
+	}
+
+	expected := make(map[string]map[setting.TopologyBinding]bool)
+	for _, b := range t.Bindings {
+		if expected[b.Queue] == nil {
+			expected[b.Queue] = make(map[setting.TopologyBinding]bool)
+		}
+		expected[b.Queue][b] = true
+	}
+
+	queues := make([]string, 0, len(expected))
+	for queue := range expected {
+		queues = append(queues, queue)
+	}
+	sort.Strings(queues)
+
+	for _, queue := range queues {
+		if !existingQueues[queue] {
+			// Already reported as a missing queue
+			continue
+		}
+
+		actual, err := client.queueBindings(ctx, queue)
+		if err != nil {
+			return nil, err
+		}
+
+		found := make(map[setting.TopologyBinding]bool)
+		for _, b := range actual {
+			if b.Source == "" || b.DestinationType != "queue" {
+				// Implicit binding to the default exchange
+				continue
+			}
+
+			binding := setting.TopologyBinding{Exchange: b.Source, Queue: b.Destination, RoutingKey: b.RoutingKey}
+			found[binding] = true
+			if !expected[queue][binding] {
+				drifts = append(drifts, Drift{"binding", bindingName(binding), "not in configured topology"})
+			}
+		}
+
+		for binding := range expected[queue] {
+			if !found[binding] {
+				drifts = append(drifts, Drift{"binding", bindingName(binding), "missing"})
+			}
+		}
+	}
+
+	return drifts, nil
+}
+
+func compareArguments(queue string, expected map[string]interface{}, actual map[string]interface{}) []Drift {
+	var drifts []Drift
+
+	for _, key := range sortedKeys(expected) {
+		value := expected[key]
+		actualValue, exists := actual[key]
+		if !exists {
+			if key == "x-queue-type" && value == "classic" {
+				// Older brokers don't report the default queue type
+				continue
+			}
+			drifts = append(drifts, Drift{"queue", queue, fmt.Sprintf("argument %s missing, expected %v", key, value)})
+			continue
+		}
+		if fmt.Sprint(normalizeArgument(actualValue)) != fmt.Sprint(value) {
+			drifts = append(drifts, Drift{"queue", queue, fmt.Sprintf("argument %s is %v, expected %v", key, actualValue, value)})
+		}
+	}
+
+	for _, key := range sortedKeys(actual) {
+		if _, exists := expected[key]; !exists && key != "x-queue-type" {
+			drifts = append(drifts, Drift{"queue", queue, fmt.Sprintf("unexpected argument %s=%v", key, actual[key])})
+		}
+	}
+
+	return drifts
+}
+
+func bindingName(b setting.TopologyBinding) string {
+	return fmt.Sprintf("%s -> %s (%s)", b.Exchange, b.Queue, b.RoutingKey)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
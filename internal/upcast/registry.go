@@ -0,0 +1,157 @@
+package upcast
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"event_service/internal/common"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Upcaster migrates a payload in place from one schema version of its topic
+// to the next
+type Upcaster func(payload map[string]interface{}) error
+
+var (
+	upcastersMu sync.RWMutex
+	upcasters   = make(map[string]map[int]Upcaster) // topic -> from version -> upcaster
+)
+
+// Register makes upcaster migrate the payloads of topic from version from to
+// from+1, replacing any upcaster previously registered for that step
+func Register(topic string, from int, upcaster Upcaster) {
+	upcastersMu.Lock()
+	defer upcastersMu.Unlock()
+
+	if upcasters[topic] == nil {
+		upcasters[topic] = make(map[int]Upcaster)
+	}
+	upcasters[topic][from] = upcaster
+}
+
+// CurrentVersion is the schema version payloads of topic are upcast to: one
+// past its newest upcaster, 1 when it has none
+func CurrentVersion(topic string) int {
+	upcastersMu.RLock()
+	defer upcastersMu.RUnlock()
+
+	current := 1
+	for from := range upcasters[topic] {
+		if from+1 > current {
+			current = from + 1
+		}
+	}
+	return current
+}
+
+// Upcast migrates payload from version to the current version of topic and
+// returns the migrated copy and the version it is in. Version 0 means the
+// event carried no schemaVersion and is read as version 1. Payloads at or
+// past the current version, and payloads an upcaster fails on, are returned
+// unchanged.
+func Upcast(topic string, version int, payload map[string]interface{}) (map[string]interface{}, int, error) {
+	if version <= 0 {
+		version = 1
+	}
+
+	current := CurrentVersion(topic)
+	if version >= current {
+		return payload, version, nil
+	}
+
+	upcasted := plainCopy(payload)
+	for step := version; step < current; step++ {
+		upcastersMu.RLock()
+		upcaster, exists := upcasters[topic][step]
+		upcastersMu.RUnlock()
+
+		if !exists {
+			return payload, version, fmt.Errorf("%w: %s has no upcaster from version %d", common.ErrUpcast, topic, step)
+		}
+
+		if err := upcaster(upcasted); err != nil {
+			return payload, version, fmt.Errorf("%w: %s version %d: %v", common.ErrUpcast, topic, step, err)
+		}
+	}
+
+	return upcasted, current, nil
+}
+
+// plainCopy deep-copies a payload, converting the nested documents of one
+// read back from MongoDB, primitive.M or primitive.D, to
+// map[string]interface{} so upcasters only deal with one object type
+func plainCopy(object map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		copied[key] = plainValue(value)
+	}
+	return copied
+}
+
+func plainValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return plainCopy(v)
+	case primitive.M:
+		return plainCopy(v)
+	case primitive.D:
+		object := make(map[string]interface{}, len(v))
+		for _, element := range v {
+			object[element.Key] = plainValue(element.Value)
+		}
+		return object
+	case primitive.A:
+		return plainSlice(v)
+	case []interface{}:
+		return plainSlice(v)
+	default:
+		return value
+	}
+}
+
+func plainSlice(values []interface{}) []interface{} {
+	copied := make([]interface{}, len(values))
+	for i, value := range values {
+		copied[i] = plainValue(value)
+	}
+	return copied
+}
+
+// Verify checks that every topic has an upcaster for each version from 1 to
+// its current one
+func Verify() error {
+	upcastersMu.RLock()
+	defer upcastersMu.RUnlock()
+
+	for topic, steps := range upcasters {
+		versions := make([]int, 0, len(steps))
+		for from := range steps {
+			versions = append(versions, from)
+		}
+		sort.Ints(versions)
+
+		for i, from := range versions {
+			if from != i+1 {
+				return fmt.Errorf("%w: %s has no upcaster from version %d", common.ErrUpcast, topic, i+1)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Topics returns the topics with upcasters in a stable order
+func Topics() []string {
+	upcastersMu.RLock()
+	defer upcastersMu.RUnlock()
+
+	topics := make([]string, 0, len(upcasters))
+	for topic := range upcasters {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	return topics
+}
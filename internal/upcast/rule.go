@@ -0,0 +1,119 @@
+package upcast
+
+import (
+	"fmt"
+	"strings"
+
+	"event_service/pkg/setting"
+)
+
+// FromRule builds the upcaster a configured rule describes
+func FromRule(rule setting.UpcasterRule) (Upcaster, error) {
+	if rule.Topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+	if rule.FromVersion < 1 {
+		return nil, fmt.Errorf("from_version must be at least 1")
+	}
+	if len(rule.Rename) == 0 && len(rule.Remove) == 0 && len(rule.Defaults) == 0 {
+		return nil, fmt.Errorf("at least one of rename, remove or defaults is required")
+	}
+
+	for _, rename := range rule.Rename {
+		if rename.From == "" || rename.To == "" {
+			return nil, fmt.Errorf("rename needs both from and to")
+		}
+	}
+	for _, p := range rule.Remove {
+		if p == "" {
+			return nil, fmt.Errorf("remove paths must not be empty")
+		}
+	}
+	for _, d := range rule.Defaults {
+		if d.Path == "" {
+			return nil, fmt.Errorf("default path is required")
+		}
+	}
+
+	return func(payload map[string]interface{}) error {
+		for _, rename := range rule.Rename {
+			if value, exists := take(payload, strings.Split(rename.From, ".")); exists {
+				if err := put(payload, strings.Split(rename.To, "."), value); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, p := range rule.Remove {
+			take(payload, strings.Split(p, "."))
+		}
+
+		for _, d := range rule.Defaults {
+			segments := strings.Split(d.Path, ".")
+			if _, exists := lookup(payload, segments); !exists {
+				if err := put(payload, segments, d.Value); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}, nil
+}
+
+func lookup(payload map[string]interface{}, segments []string) (interface{}, bool) {
+	current := payload
+	for i, segment := range segments {
+		value, exists := current[segment]
+		if !exists {
+			return nil, false
+		}
+		if i == len(segments)-1 {
+			return value, true
+		}
+		if current, exists = value.(map[string]interface{}); !exists {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// take removes and returns the value at segments
+func take(payload map[string]interface{}, segments []string) (interface{}, bool) {
+	parent, exists := lookup(payload, segments[:len(segments)-1])
+	if len(segments) == 1 {
+		parent, exists = payload, true
+	}
+	object, isObject := parent.(map[string]interface{})
+	if !exists || !isObject {
+		return nil, false
+	}
+
+	last := segments[len(segments)-1]
+	value, exists := object[last]
+	delete(object, last)
+	return value, exists
+}
+
+// put sets value at segments, creating intermediate objects
+func put(payload map[string]interface{}, segments []string, value interface{}) error {
+	current := payload
+	for _, segment := range segments[:len(segments)-1] {
+		next, exists := current[segment]
+		if !exists {
+			child := make(map[string]interface{})
+			current[segment] = child
+			current = child
+			continue
+		}
+
+		child, isObject := next.(map[string]interface{})
+		if !isObject {
+			return fmt.Errorf("%s is not an object", segment)
+		}
+		current = child
+	}
+
+	current[segments[len(segments)-1]] = value
+	return nil
+}
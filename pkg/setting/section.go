@@ -2,38 +2,315 @@ package setting
 
 // MongoDB configuration
 type MongoDB struct {
-	Host                  string `mapstructure:"host"`
-	Port                  int    `mapstructure:"port"`
-	Database              string `mapstructure:"database"`
-	ActivityLogCollection string `mapstructure:"activity_log_collection"`
-	User                  string `mapstructure:"user"`
-	Password              string `mapstructure:"password"`
-	ConnectionString      string `mapstructure:"connection_string"`
+	Host                  string         `mapstructure:"host"`
+	Port                  int            `mapstructure:"port"`
+	Database              string         `mapstructure:"database"`
+	ActivityLogCollection string         `mapstructure:"activity_log_collection"`
+	User                  string         `mapstructure:"user"`
+	Password              string         `mapstructure:"password"`
+	ConnectionString      string         `mapstructure:"connection_string"`
+	CircuitBreaker        CircuitBreaker `mapstructure:"circuit_breaker"`
+}
+
+// CircuitBreaker configuration for MongoDB writes
+type CircuitBreaker struct {
+	Enabled              bool    `mapstructure:"enabled"`
+	WindowSize           int     `mapstructure:"window_size"`
+	MinRequests          int     `mapstructure:"min_requests"`
+	FailureRateThreshold float64 `mapstructure:"failure_rate_threshold"`
+	OpenSeconds          int     `mapstructure:"open_seconds"`
+	HalfOpenMaxProbes    int     `mapstructure:"half_open_max_probes"`
+}
+
+// Spool configuration (local write-ahead buffer used while MongoDB is unavailable)
+type Spool struct {
+	Enabled              bool   `mapstructure:"enabled"`
+	Directory            string `mapstructure:"directory"`
+	SegmentMaxBytes      int64  `mapstructure:"segment_max_bytes"`
+	MaxTotalBytes        int64  `mapstructure:"max_total_bytes"`
+	FsyncPolicy          string `mapstructure:"fsync_policy"` // always, interval or never
+	FsyncIntervalMs      int    `mapstructure:"fsync_interval_ms"`
+	DrainIntervalSeconds int    `mapstructure:"drain_interval_seconds"`
+}
+
+// LeaderElection configuration (MongoDB leases for singleton background jobs)
+type LeaderElection struct {
+	LeaseTTLSeconds      int `mapstructure:"lease_ttl_seconds"`
+	RenewIntervalSeconds int `mapstructure:"renew_interval_seconds"`
+	MaxClockSkewMs       int `mapstructure:"max_clock_skew_ms"`
+}
+
+// Scheduler configuration (periodic background jobs)
+type Scheduler struct {
+	Enabled bool                    `mapstructure:"enabled"`
+	Jobs    map[string]ScheduledJob `mapstructure:"jobs"`
+}
+
+// ScheduledJob configuration: a registered job run on a cron schedule
+type ScheduledJob struct {
+	Job            string                 `mapstructure:"job"` // registered job, defaults to the key under scheduler.jobs
+	Schedule       string                 `mapstructure:"schedule"`
+	TimeoutSeconds int                    `mapstructure:"timeout_seconds"`
+	Enabled        bool                   `mapstructure:"enabled"`
+	AllInstances   bool                   `mapstructure:"all_instances"` // run on every replica instead of the lease holder
+	Params         map[string]interface{} `mapstructure:"params"`
+}
+
+// Erasure configuration (right-to-erasure of a user's activity logs)
+type Erasure struct {
+	Mode          string   `mapstructure:"mode"`           // anonymize (default) or delete
+	FieldPaths    []string `mapstructure:"field_paths"`    // payload paths that may hold the user id, e.g. "createdById", "member.id"
+	UserIDPath    string   `mapstructure:"user_id_path"`   // payload path of the deleted user id in user.deleted.log events
+	PseudonymSalt string   `mapstructure:"pseudonym_salt"` // HMAC key for pseudonyms; keep it secret and stable
+	BatchSize     int      `mapstructure:"batch_size"`
+
+	// Deadline of one user_erasure run; an unfinished run resumes from its
+	// receipt checkpoint on retry. Keep it below RabbitMQ's consumer_timeout
+	// (30 minutes by default), or the broker closes the channel.
+	TimeoutSeconds int `mapstructure:"timeout_seconds"`
+}
+
+// Redaction configuration: PII rules applied to payloads before they are
+// stored
+type Redaction struct {
+	Enabled bool            `mapstructure:"enabled"`
+	HashKey string          `mapstructure:"hash_key"` // HMAC key for the hash action; keep it secret and stable
+	Rules   []RedactionRule `mapstructure:"rules"`
+}
+
+// RedactionRule redacts the values at Paths, or the text matched by a
+// detector (anywhere in the payload, or under Paths when set)
+type RedactionRule struct {
+	Name     string   `mapstructure:"name"`
+	Topics   []string `mapstructure:"topics"`   // topic patterns such as "user.*.log"; all topics when empty
+	Paths    []string `mapstructure:"paths"`    // dot-separated payload paths, "*" matches any key
+	Detector string   `mapstructure:"detector"` // email, phone, ipv4, ipv6, ip
+	Pattern  string   `mapstructure:"pattern"`  // custom regular expression, instead of a detector
+	Action   string   `mapstructure:"action"`   // mask, hash or drop
+}
+
+// Schema configuration: payload upcasters migrating older schema versions of
+// a topic to its current shape
+type Schema struct {
+	UpcastOnRead bool           `mapstructure:"upcast_on_read"` // also upcast stored logs when they are read
+	Upcasters    []UpcasterRule `mapstructure:"upcasters"`
+}
+
+// UpcasterRule migrates the payloads of Topic from FromVersion to
+// FromVersion+1: renames first, then removals, then defaults
+type UpcasterRule struct {
+	Topic       string         `mapstructure:"topic"`
+	FromVersion int            `mapstructure:"from_version"`
+	Rename      []FieldRename  `mapstructure:"rename"`
+	Remove      []string       `mapstructure:"remove"`   // dot-separated payload paths
+	Defaults    []FieldDefault `mapstructure:"defaults"` // set only when the path is absent
+}
+
+// FieldRename moves the value at From to To (dot-separated payload paths)
+type FieldRename struct {
+	From string `mapstructure:"from"`
+	To   string `mapstructure:"to"`
+}
+
+// FieldDefault sets Value at Path when the payload has no value there
+type FieldDefault struct {
+	Path  string      `mapstructure:"path"`
+	Value interface{} `mapstructure:"value"`
+}
+
+// Encryption configuration: envelope encryption of sensitive payload fields
+type Encryption struct {
+	Enabled      bool             `mapstructure:"enabled"`
+	KeyFile      string           `mapstructure:"key_file"`      // JSON file holding the master keys
+	DecryptToken string           `mapstructure:"decrypt_token"` // X-Decrypt-Token that lets admin reads see plaintext
+	Rules        []EncryptionRule `mapstructure:"rules"`
+}
+
+// EncryptionRule encrypts the values at Paths in the events of Topics
+type EncryptionRule struct {
+	Topics []string `mapstructure:"topics"` // topic patterns such as "member.*.log"; all topics when empty
+	Paths  []string `mapstructure:"paths"`  // dot-separated payload paths
+}
+
+// Audit configuration (tamper evidence for stored activity logs)
+type Audit struct {
+	HashChain         bool   `mapstructure:"hash_chain"`          // chain each stored log to the previous one of its partition
+	ChainPartitionKey string `mapstructure:"chain_partition_key"` // payload path partitioning the chain, e.g. "workspaceId"
+
+	CheckpointKeyID      string `mapstructure:"checkpoint_key_id"`      // names the signing key in every checkpoint
+	CheckpointSigningKey string `mapstructure:"checkpoint_signing_key"` // base64 Ed25519 seed or private key
 }
 
 // RabbitMQ configuration
 type RabbitMQ struct {
-	Host                  string `mapstructure:"host"`
-	Port                  int    `mapstructure:"port"`
-	User                  string `mapstructure:"user"`
-	Password              string `mapstructure:"password"`
+	Host          string                 `mapstructure:"host"`
+	Port          int                    `mapstructure:"port"`
+	User          string                 `mapstructure:"user"`
+	Password      string                 `mapstructure:"password"`
+	ManagementURL string                 `mapstructure:"management_url"` // used by `topology verify`
+	Sources       map[string]EventSource `mapstructure:"sources"`
+	Consumers     map[string]Consumer    `mapstructure:"consumers"`
+
+	// Defaults for consumers that don't set their own
+	PrefetchCount     int      `mapstructure:"prefetch_count"`
+	WorkerCount       int      `mapstructure:"worker_count"`
+	OrderingKeys      []string `mapstructure:"ordering_keys"` // first path present in the message wins
+	RetryAttempts     int      `mapstructure:"retry_attempts"`
+	RetryDelaySeconds int      `mapstructure:"retry_delay_seconds"`
+
+	PropertyFallback PropertyFallback `mapstructure:"property_fallback"`
+
+	// Deprecated: single-queue settings, mapped onto Sources when no sources
+	// are configured
 	IAMExchange           string `mapstructure:"iam_exchange"`
 	AppStoreExchange      string `mapstructure:"app_store_exchange"`
 	ActivityLogQueue      string `mapstructure:"activity_log_queue"`
 	ActivityLogBindingKey string `mapstructure:"activity_log_binding_key"`
-	RetryAttempts         int    `mapstructure:"retry_attempts"`
-	RetryDelaySeconds     int    `mapstructure:"retry_delay_seconds"`
+}
+
+// EventSource configuration: an exchange delivered to its own queue and
+// dead letter queue
+type EventSource struct {
+	Name            string `mapstructure:"name"` // defaults to the key under rabbitmq.sources
+	Exchange        string `mapstructure:"exchange"`
+	Queue           string `mapstructure:"queue"`
+	BindingKey      string `mapstructure:"binding_key"`
+	DeadLetterQueue string `mapstructure:"dead_letter_queue"`
+}
+
+// PropertyFallback fills the envelope fields a message lacks from its AMQP
+// properties. Each field lists sources tried in order: message_id,
+// correlation_id, timestamp, app_id, type, user_id, routing_key or
+// header:<name>.
+type PropertyFallback struct {
+	Enabled         bool     `mapstructure:"enabled"`
+	EventID         []string `mapstructure:"event_id"`
+	Timestamp       []string `mapstructure:"timestamp"`
+	SourceService   []string `mapstructure:"source_service"`
+	Topic           []string `mapstructure:"topic"`
+	PayloadFromBody bool     `mapstructure:"payload_from_body"` // a JSON body without a payload field is the payload
+	Headers         []string `mapstructure:"headers"`           // AMQP headers stored on the activity log as metadata
+}
+
+// Verification configuration: checks that an event's claimed topic and
+// source match its delivery. Violating events are quarantined, not stored.
+type Verification struct {
+	Enabled                bool           `mapstructure:"enabled"`
+	RoutingKeyMatchesTopic bool           `mapstructure:"routing_key_matches_topic"`
+	Users                  []VerifiedUser `mapstructure:"users"`  // checked when not empty; other AMQP users are rejected
+	Topics                 []TopicSources `mapstructure:"topics"` // topics matched by no entry accept any source
+}
+
+// VerifiedUser lists the source services an AMQP user-id may publish as
+type VerifiedUser struct {
+	User    string   `mapstructure:"user"`
+	Sources []string `mapstructure:"sources"`
+}
+
+// TopicSources lists the source services allowed to publish Topics
+type TopicSources struct {
+	Topics  []string `mapstructure:"topics"` // topic patterns such as "user.*.log"
+	Sources []string `mapstructure:"sources"`
+}
+
+// Consumer configuration: a queue consumed by a registered handler. Queue,
+// exchange and dead letter queue default to those of the referenced source.
+type Consumer struct {
+	Name              string   `mapstructure:"name"` // defaults to the key under rabbitmq.consumers
+	Source            string   `mapstructure:"source"`
+	Queue             string   `mapstructure:"queue"`
+	Exchange          string   `mapstructure:"exchange"` // origin exchange recorded when the delivery doesn't carry one
+	DeadLetterQueue   string   `mapstructure:"dead_letter_queue"`
+	RetryQueue        string   `mapstructure:"retry_queue"` // delay queue retries wait in; defaults to "<queue>.retry"
+	Handler           string   `mapstructure:"handler"`
+	PrefetchCount     int      `mapstructure:"prefetch_count"`
+	WorkerCount       int      `mapstructure:"worker_count"`
+	OrderingKeys      []string `mapstructure:"ordering_keys"`
+	RetryAttempts     int      `mapstructure:"retry_attempts"`
+	RetryDelaySeconds int      `mapstructure:"retry_delay_seconds"`
+
+	// Queue declaration; ignored when the queue is listed under topology.queues
+	QueueType     string `mapstructure:"queue_type"`     // classic (default), quorum, stream
+	DeliveryLimit int    `mapstructure:"delivery_limit"` // quorum only: broker-enforced redelivery cap
+
+	// Only one replica receives deliveries at a time; the others stay
+	// subscribed as hot standbys
+	SingleActiveConsumer bool `mapstructure:"single_active_consumer"`
+
+	// Stream consumers only
+	StreamOffset          string `mapstructure:"stream_offset"`           // first (default), last, next, timestamp, stored
+	StreamOffsetTimestamp string `mapstructure:"stream_offset_timestamp"` // RFC 3339, with stream_offset "timestamp"
+}
+
+// Topology configuration: broker objects declared idempotently at startup.
+// Queues and bindings implied by rabbitmq.sources and rabbitmq.consumers are
+// added automatically unless a queue of the same name is listed here.
+type Topology struct {
+	Exchanges []TopologyExchange `mapstructure:"exchanges"`
+	Queues    []TopologyQueue    `mapstructure:"queues"`
+	Bindings  []TopologyBinding  `mapstructure:"bindings"`
+}
+
+type TopologyExchange struct {
+	Name       string `mapstructure:"name"`
+	Type       string `mapstructure:"type"` // direct, fanout, topic, headers
+	Durable    bool   `mapstructure:"durable"`
+	AutoDelete bool   `mapstructure:"auto_delete"`
+}
+
+type TopologyQueue struct {
+	Name                 string                 `mapstructure:"name"`
+	Durable              bool                   `mapstructure:"durable"`
+	AutoDelete           bool                   `mapstructure:"auto_delete"`
+	QueueType            string                 `mapstructure:"queue_type"`     // classic, quorum, stream
+	DeliveryLimit        int                    `mapstructure:"delivery_limit"` // quorum only
+	SingleActiveConsumer bool                   `mapstructure:"single_active_consumer"`
+	DeadLetterExchange   string                 `mapstructure:"dead_letter_exchange"`
+	DeadLetterRoutingKey string                 `mapstructure:"dead_letter_routing_key"`
+	MessageTTLMs         int                    `mapstructure:"message_ttl_ms"`
+	MaxLength            int                    `mapstructure:"max_length"`
+	MaxLengthBytes       int64                  `mapstructure:"max_length_bytes"`
+	Overflow             string                 `mapstructure:"overflow"` // drop-head, reject-publish, reject-publish-dlx
+	Arguments            map[string]interface{} `mapstructure:"arguments"`
+}
+
+type TopologyBinding struct {
+	Exchange   string `mapstructure:"exchange"`
+	Queue      string `mapstructure:"queue"`
+	RoutingKey string `mapstructure:"routing_key"`
 }
 
 // Server configuration
 type Server struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
+	Host                   string `mapstructure:"host"`
+	Port                   int    `mapstructure:"port"`
+	ShutdownTimeoutSeconds int    `mapstructure:"shutdown_timeout_seconds"`
+	InstanceID             string `mapstructure:"instance_id"` // defaults to <hostname>-<pid>
+	AdminToken             string `mapstructure:"admin_token"` // bearer token required on /admin endpoints; disabled when unset
+}
+
+// Startup configuration (dependency connection retries)
+type Startup struct {
+	TimeoutSeconds    int     `mapstructure:"timeout_seconds"`
+	InitialBackoffMs  int     `mapstructure:"initial_backoff_ms"`
+	MaxBackoffMs      int     `mapstructure:"max_backoff_ms"`
+	BackoffMultiplier float64 `mapstructure:"backoff_multiplier"`
 }
 
 // Main configuration struct
 type Config struct {
-	Server   Server   `mapstructure:"server"`
-	MongoDB  MongoDB  `mapstructure:"mongodb"`
-	RabbitMQ RabbitMQ `mapstructure:"rabbitmq"`
+	Server         Server         `mapstructure:"server"`
+	Startup        Startup        `mapstructure:"startup"`
+	MongoDB        MongoDB        `mapstructure:"mongodb"`
+	Spool          Spool          `mapstructure:"spool"`
+	LeaderElection LeaderElection `mapstructure:"leader_election"`
+	Scheduler      Scheduler      `mapstructure:"scheduler"`
+	Erasure        Erasure        `mapstructure:"erasure"`
+	Redaction      Redaction      `mapstructure:"redaction"`
+	Schema         Schema         `mapstructure:"schema"`
+	Encryption     Encryption     `mapstructure:"encryption"`
+	Audit          Audit          `mapstructure:"audit"`
+	Verification   Verification   `mapstructure:"verification"`
+	RabbitMQ       RabbitMQ       `mapstructure:"rabbitmq"`
+	Topology       Topology       `mapstructure:"topology"`
 }